@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package account
+
+import "github.com/spf13/cobra"
+
+func NewAccountCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage Bluesky accounts",
+	}
+	cmd.AddCommand(newCreateCommand())
+
+	return cmd
+}
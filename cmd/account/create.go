@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package account
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var (
+	handle     string
+	email      string
+	password   string
+	inviteCode string
+)
+
+func newCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an account on a PDS that allows signups",
+		Long: `Create an account on a PDS that allows signups, and store its session.
+
+The PDS to sign up on is picked with the global --pds flag or YABC_PDS. The
+handle must be under one of the domains the PDS serves, and some servers
+require an invite code. Without --password, the password is prompted for.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !bluesky.PDSOverridden() {
+				fmt.Println("Error: pick the PDS to sign up on with --pds or YABC_PDS")
+				return
+			}
+
+			if password == "" {
+				err := huh.NewInput().
+					Title("Choose a password").
+					EchoMode(huh.EchoModePassword).
+					Value(&password).
+					Run()
+				if err != nil {
+					prompt.Exit(err)
+				}
+			}
+
+			session, err := bluesky.CreateAccount(bluesky.NewAccount{
+				Handle:     handle,
+				Email:      email,
+				Password:   password,
+				InviteCode: inviteCode,
+			})
+			if err != nil {
+				slog.Error("Failed to create account", "pds", bluesky.PDS(), "handle", handle, "error", err)
+				fmt.Println("Error:", err)
+				return
+			}
+
+			if err := bluesky.SaveSession(session); err != nil {
+				slog.Error("Failed to save session", "error", err)
+				fmt.Println("Warning: Account created, but the session could not be saved")
+			}
+
+			fmt.Printf("Account created: %s (%s)\n", session.Handle, session.DID)
+		},
+	}
+
+	cmd.Flags().StringVar(&handle, "handle", "", "Handle of the new account, e.g. alice.pds.example.com")
+	cmd.Flags().StringVar(&email, "email", "", "Email address of the new account")
+	cmd.Flags().StringVar(&password, "password", "", "Password of the new account (prompted for if omitted)")
+	cmd.Flags().StringVar(&inviteCode, "invite-code", "", "Invite code, if the PDS requires one")
+	cmd.MarkFlagRequired("handle")
+	cmd.MarkFlagRequired("email")
+
+	examples.SetValue(cmd, "handle", "alice.pds.example.com")
+	examples.SetValue(cmd, "email", "alice@example.com")
+	examples.SetValue(cmd, "invite-code", "pds-example-com-abcde-fghij")
+	examples.Add(cmd, "", "pds", "handle", "email")
+	examples.Add(cmd, "", "pds", "handle", "email", "invite-code")
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package apppasswords
+
+import "github.com/spf13/cobra"
+
+func NewAppPasswordsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app-passwords",
+		Short: "Manage the app passwords tied to your account",
+		Long: `Manage the app passwords tied to your account.
+
+Bluesky only lets the main account password manage app passwords, so these
+commands require BLUESKY_PASSWORD to be your main password.`,
+	}
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newRevokeCommand())
+
+	return cmd
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package apppasswords
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/spf13/cobra"
+)
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your app passwords",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			passwords, err := bluesky.ListAppPasswords(token)
+			if errors.Is(err, bluesky.ErrAppPasswordSession) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to list app passwords", "error", err)
+				fmt.Println("Error: Failed to list app passwords")
+				return
+			}
+
+			if len(passwords) == 0 {
+				fmt.Println("No app passwords")
+				return
+			}
+
+			for _, password := range passwords {
+				privileged := ""
+				if password.Privileged {
+					privileged = " (privileged)"
+				}
+				fmt.Printf("%-30s created %s%s\n", password.Name, password.CreatedAt, privileged)
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package apppasswords
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var (
+	yes bool
+)
+
+func newRevokeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke <name>",
+		Short: "Revoke an app password",
+		Long:  `Revoke an app password by name. Any client using it will be logged out.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+
+			if !yes {
+				confirmed := false
+				err := huh.NewConfirm().
+					Title(fmt.Sprintf("Revoke app password %q?", name)).
+					Description("Clients using it will be logged out.").
+					Value(&confirmed).
+					Run()
+				if err != nil {
+					prompt.Exit(err)
+				}
+				if !confirmed {
+					return
+				}
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			err = bluesky.RevokeAppPassword(token, name)
+			if errors.Is(err, bluesky.ErrAppPasswordSession) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to revoke app password", "name", name, "error", err)
+				fmt.Println("Error: Failed to revoke app password")
+				return
+			}
+
+			fmt.Printf("App password %q revoked\n", name)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Don't ask for confirmation")
+
+	examples.Add(cmd, `"old laptop"`)
+	examples.Add(cmd, `"old laptop"`, "yes")
+
+	return cmd
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package auth
+
+import "github.com/spf13/cobra"
+
+func NewAuthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Log in and out of Bluesky",
+	}
+	cmd.AddCommand(NewLoginCommand())
+	cmd.AddCommand(NewLogoutCommand())
+	cmd.AddCommand(NewWhoamiCommand())
+	cmd.AddCommand(newSwitchCommand())
+	cmd.AddCommand(newListCommand())
+
+	return cmd
+}
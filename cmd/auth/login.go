@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/oauth"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var (
+	useOAuth bool
+)
+
+// NewLoginCommand is also registered at the top level as "yabc login"
+func NewLoginCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login [handle]",
+		Short: "Log in with an app password, or with OAuth in the browser",
+		Long: `Log in to Bluesky and save the session for later commands.
+
+By default, yabc asks for your handle and an app password (taken from
+BLUESKY_IDENTIFIER and BLUESKY_PASSWORD when set, or for a profile, from the
+same suffixed with its name, like BLUESKY_IDENTIFIER_WORK), checks them and
+saves the session to ~/.config/yabc/session.json.
+
+With --oauth, yabc opens your browser to approve access instead, so no
+password is shared with it, and stores the tokens in ~/.config/yabc/oauth.json.
+Use an app password on a PDS without OAuth.
+
+With --profile, the session is saved for that profile instead, under
+~/.config/yabc/profiles/<name>/, so several accounts can stay logged in.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			handle, password := bluesky.Credentials()
+			if len(args) > 0 {
+				handle = args[0]
+			}
+			if handle == "" {
+				err := huh.NewInput().
+					Title("Your handle").
+					Placeholder("alice.bsky.social").
+					Value(&handle).
+					Run()
+				if err != nil {
+					prompt.Exit(err)
+				}
+			}
+
+			if useOAuth {
+				loginWithOAuth(cmd, handle)
+				return
+			}
+
+			if password == "" {
+				err := huh.NewInput().
+					Title("App password").
+					Description("Create one at " + bluesky.AppPasswordsURL).
+					Placeholder("xxxx-xxxx-xxxx-xxxx").
+					EchoMode(huh.EchoModePassword).
+					Value(&password).
+					Run()
+				if err != nil {
+					prompt.Exit(err)
+				}
+			}
+
+			token, err := bluesky.Login(handle, password)
+			if errors.Is(err, bluesky.ErrMainPassword) {
+				if err = confirmMainPassword(); err == nil {
+					token, err = bluesky.LoginMainPassword(handle, password)
+				}
+			}
+			if errors.Is(err, bluesky.ErrNotAppPassword) {
+				fmt.Println("Cancelled.")
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to log in", "handle", handle, "error", err)
+				fmt.Println("Error: Failed to log in, check your handle and app password")
+				return
+			}
+
+			if err := bluesky.SaveSession(token); err != nil {
+				slog.Error("Failed to save session", "error", err)
+				fmt.Println("Error: Logged in, but failed to save the session")
+				return
+			}
+			// An OAuth session would take precedence over the one just saved
+			if err := oauth.Remove(); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			fmt.Printf("Logged in as @%s (%s)\n", token.Handle, token.DID)
+		},
+	}
+
+	cmd.Flags().BoolVar(&useOAuth, "oauth", false, "Log in through the browser with OAuth instead of an app password")
+
+	examples.Add(cmd, "")
+	examples.Add(cmd, "alice.bsky.social")
+	examples.Add(cmd, "alice.bsky.social", "oauth")
+	examples.Add(cmd, "alice.bsky.social", "profile")
+
+	return cmd
+}
+
+// confirmMainPassword warns about logging in with what looks like the
+// account's main password and asks to go on, returning
+// bluesky.ErrNotAppPassword if declined. When there is no terminal to ask on,
+// the warning alone has to do, so that scripts keep working.
+func confirmMainPassword() error {
+	fmt.Println("Warning: this doesn't look like an app password (xxxx-xxxx-xxxx-xxxx).")
+	fmt.Println("Your main password gives full control of your account, please create an app password at", bluesky.AppPasswordsURL)
+
+	confirmed := false
+	err := huh.NewConfirm().
+		Title("Log in with this password anyway?").
+		Description("Set YABC_ALLOW_MAIN_PASSWORD=1 to skip this question.").
+		Value(&confirmed).
+		Run()
+	if errors.Is(err, huh.ErrUserAborted) || (err == nil && !confirmed) {
+		return bluesky.ErrNotAppPassword
+	}
+	if err != nil {
+		slog.Debug("Could not ask to confirm main password", "error", err)
+	}
+	return nil
+}
+
+// loginWithOAuth runs the browser flow for handle and saves the OAuth session
+func loginWithOAuth(cmd *cobra.Command, handle string) {
+	identity, err := bluesky.ResolveIdentity(handle)
+	if err != nil {
+		slog.Error("Failed to resolve handle", "handle", handle, "error", err)
+		fmt.Println("Error: Failed to find the account", handle)
+		return
+	}
+
+	session, err := oauth.Login(cmd.Context(), oauth.Account{
+		DID:    identity.DID,
+		Handle: identity.Handle,
+		PDS:    identity.PDS,
+	}, func(authURL string) {
+		fmt.Println("Opening your browser to approve yabc. If it doesn't open, visit:")
+		fmt.Println(authURL)
+	})
+	if errors.Is(err, oauth.ErrNotSupported) {
+		slog.Error("OAuth not available", "pds", identity.PDS, "error", err)
+		fmt.Printf("Error: %s doesn't support OAuth yet\n", identity.PDS)
+		fmt.Println("Log in with an app password instead: create one at", bluesky.AppPasswordsURL)
+		fmt.Println("and run `yabc auth login` without --oauth")
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to log in with OAuth", "error", err)
+		fmt.Println("Error:", err)
+		return
+	}
+
+	if err := session.Save(); err != nil {
+		slog.Error("Failed to save OAuth session", "error", err)
+		fmt.Println("Error: Logged in, but failed to save the session")
+		return
+	}
+
+	fmt.Printf("Logged in as @%s (%s)\n", session.Handle, session.DID)
+}
+
+// NewLogoutCommand is also registered at the top level as "yabc logout"
+func NewLogoutCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Delete the saved session",
+		Long: `Delete the sessions saved by "yabc login", whether from an app password or
+OAuth. Later commands log in with BLUESKY_IDENTIFIER and BLUESKY_PASSWORD, or
+their profile's suffixed ones, like BLUESKY_IDENTIFIER_WORK.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := bluesky.RemoveSession(); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err := oauth.Remove(); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			fmt.Println("Logged out.")
+		},
+	}
+
+	return cmd
+}
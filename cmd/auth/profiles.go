@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+func newSwitchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch <profile>",
+		Short: "Pick the account profile used by later commands",
+		Long: `Make a profile the one later commands use when --profile isn't given.
+Log in to a new profile with "yabc --profile <name> login".`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			if err := profile.Switch(name); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			handle, err := profileHandle(name)
+			if err != nil {
+				slog.Warn("Failed to read profile session", "profile", name, "error", err)
+			}
+			if handle == "" {
+				fmt.Printf("Switched to profile %s, which isn't logged in yet: run `yabc login`\n", name)
+				return
+			}
+			fmt.Printf("Switched to profile %s (@%s)\n", name, handle)
+		},
+	}
+
+	examples.Add(cmd, "work")
+	examples.Add(cmd, profile.Default)
+
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List account profiles and who they are logged in as",
+		Run: func(cmd *cobra.Command, args []string) {
+			names, err := profile.List()
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			active := profile.Active()
+			var rows [][]string
+			for _, name := range names {
+				marker := ""
+				if name == active {
+					marker = "*"
+				}
+
+				handle, err := profileHandle(name)
+				if err != nil {
+					slog.Warn("Failed to read profile session", "profile", name, "error", err)
+				}
+				account := "(not logged in)"
+				if handle != "" {
+					account = "@" + handle
+				}
+				rows = append(rows, []string{marker, name, account})
+			}
+			render.Table([]string{"", "PROFILE", "ACCOUNT"}, rows)
+		},
+	}
+
+	examples.Add(cmd, "")
+
+	return cmd
+}
+
+// profileHandle returns the handle a profile is logged in as, preferring its
+// OAuth session as GetToken does, or "" if it has no session
+func profileHandle(name string) (string, error) {
+	account, err := savedAccount(name)
+	if err != nil || account == nil {
+		return "", err
+	}
+	return account.Handle, nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/oauth"
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/spf13/cobra"
+)
+
+var (
+	whoamiJSON  bool
+	whoamiCheck bool
+)
+
+// Ways a session was logged in
+const (
+	authOAuth       = "oauth"
+	authAppPassword = "app-password"
+	authPassword    = "password"
+)
+
+// account is who a profile is logged in as, as written by --json
+type account struct {
+	Profile string `json:"profile"`
+	Handle  string `json:"handle,omitempty"`
+	DID     string `json:"did,omitempty"`
+	PDS     string `json:"pds,omitempty"`
+	Auth    string `json:"auth,omitempty"`
+	// Valid is whether the PDS accepted the session, only known once --check reached it
+	Valid *bool  `json:"valid,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewWhoamiCommand is also registered at the top level as "yabc whoami"
+func NewWhoamiCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the account commands post as",
+		Long: `Show the handle and DID of the account later commands act as, the active
+profile, and how it logged in, from the saved session. Nothing is sent to
+Bluesky unless --check is given.
+
+With --check, yabc logs in as any other command would and asks the PDS for the
+session, confirming it is still valid. Use it in scripts to make sure they
+post as the right account.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			name := profile.Active()
+			saved, err := savedAccount(name)
+			if err != nil {
+				writeWhoamiError(name, err)
+				return
+			}
+			result := &account{Profile: name}
+			if saved != nil {
+				result = saved
+			}
+
+			if whoamiCheck {
+				checked, err := checkSession()
+				if err == nil {
+					checked.Profile = name
+					result = checked
+				} else {
+					slog.Debug("Session check failed", "error", err)
+					result.Error = err.Error()
+				}
+
+				// A PDS that can't be reached says nothing about the session
+				var xrpcErr *bluesky.XRPCError
+				if err == nil || errors.As(err, &xrpcErr) {
+					valid := err == nil
+					result.Valid = &valid
+				}
+			}
+
+			if whoamiJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(result); err != nil {
+					slog.Error("Failed to encode account", "error", err)
+				}
+				if result.DID == "" || result.Error != "" {
+					os.Exit(1)
+				}
+				return
+			}
+			printAccount(result)
+		},
+	}
+
+	cmd.Flags().BoolVar(&whoamiJSON, "json", false, "Print the account as JSON, exiting with status 1 if not logged in or the check failed")
+	cmd.Flags().BoolVar(&whoamiCheck, "check", false, "Ask the PDS to confirm the session is still valid")
+
+	examples.Add(cmd, "")
+	examples.Add(cmd, "", "check")
+	examples.Add(cmd, "", "check", "json")
+
+	return cmd
+}
+
+// savedAccount returns who a profile's saved session is for, preferring its
+// OAuth session as GetToken does, or nil if it has no session
+func savedAccount(name string) (*account, error) {
+	oauthSession, err := oauth.LoadProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	if oauthSession != nil {
+		return &account{Profile: name, Handle: oauthSession.Handle, DID: oauthSession.DID, PDS: oauthSession.PDS, Auth: authOAuth}, nil
+	}
+
+	session, err := bluesky.LoadProfileSession(name)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	return &account{Profile: name, Handle: session.Handle, DID: session.DID, PDS: session.PDS, Auth: authMethod(&session.DIDResponse)}, nil
+}
+
+// checkSession logs in as other commands do and asks the PDS who the session is for
+func checkSession() (*account, error) {
+	token, err := bluesky.GetToken()
+	if err != nil {
+		return nil, err
+	}
+	session, err := bluesky.GetSession(token)
+	if err != nil {
+		return nil, err
+	}
+	return &account{Handle: session.Handle, DID: session.DID, PDS: bluesky.PDS(), Auth: authMethod(token)}, nil
+}
+
+// authMethod returns how a session was logged in
+func authMethod(token *bluesky.DIDResponse) string {
+	switch {
+	case token.IsOAuthSession():
+		return authOAuth
+	case token.IsAppPasswordSession():
+		return authAppPassword
+	}
+	return authPassword
+}
+
+// printAccount prints who a profile is logged in as
+func printAccount(a *account) {
+	if a.DID == "" {
+		fmt.Printf("Profile %s isn't logged in: run `yabc login`\n", a.Profile)
+		if identifier, _ := bluesky.Credentials(); identifier != "" && !whoamiCheck {
+			identifierVar, _ := bluesky.CredentialVars()
+			fmt.Printf("Commands will log in as %s, from %s\n", identifier, identifierVar)
+		}
+		if a.Error != "" {
+			fmt.Println("Error:", a.Error)
+		}
+		return
+	}
+
+	fmt.Printf("Logged in as @%s\n", a.Handle)
+	fmt.Println("DID:    ", a.DID)
+	fmt.Println("Profile:", a.Profile)
+	if a.PDS != "" {
+		fmt.Println("PDS:    ", a.PDS)
+	}
+	switch a.Auth {
+	case authOAuth:
+		fmt.Println("Login:   OAuth")
+	case authAppPassword:
+		fmt.Println("Login:   app password")
+	case authPassword:
+		fmt.Println("Login:   main password")
+	}
+	switch {
+	case a.Valid != nil && *a.Valid:
+		fmt.Println("Session: valid")
+	case a.Valid != nil:
+		fmt.Printf("Session: no longer valid (%s), run `yabc login` again\n", a.Error)
+	case a.Error != "":
+		fmt.Printf("Session: could not be checked (%s)\n", a.Error)
+	}
+}
+
+// writeWhoamiError reports a session that couldn't be read
+func writeWhoamiError(name string, err error) {
+	if !whoamiJSON {
+		fmt.Println("Error:", err)
+		return
+	}
+	json.NewEncoder(os.Stdout).Encode(account{Profile: name, Error: err.Error()})
+	os.Exit(1)
+}
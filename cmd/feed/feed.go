@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package feed
+
+import (
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+func NewFeedCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Read feeds on Bluesky",
+	}
+	cmd.AddCommand(newTimelineCommand())
+	render.AddTimeFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package feed
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/alexisbcz/yabc/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// firstRunWindow is how far back --since-last-run looks the first time
+	firstRunWindow = 24 * time.Hour
+	// maxCatchUpPages bounds how many pages --since-last-run fetches to catch up
+	maxCatchUpPages = 10
+)
+
+var (
+	timelineLimit        int
+	timelineCursor       string
+	timelineSinceLastRun bool
+)
+
+// maxPageLimit is the most items the API returns in one page
+const maxPageLimit = 100
+
+func newTimelineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timeline",
+		Short: "Show your home timeline",
+		Long: `Show the posts in your home timeline, newest first.
+
+Older posts are a page away: the cursor printed after the posts, passed to
+--cursor, shows the next page.
+
+With --since-last-run, only posts that arrived since the previous
+--since-last-run are shown (the last 24 hours on the first run).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if timelineLimit < 1 || timelineLimit > maxPageLimit {
+				fmt.Printf("Error: --limit must be between 1 and %d\n", maxPageLimit)
+				return
+			}
+			if timelineSinceLastRun && timelineCursor != "" {
+				fmt.Println("Error: --cursor and --since-last-run can't be used together")
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			if !timelineSinceLastRun {
+				page, err := bluesky.GetTimeline(token, timelineCursor, timelineLimit)
+				if err != nil {
+					slog.Error("Failed to get timeline", "error", err)
+					fmt.Println("Error: Failed to get timeline")
+					return
+				}
+				for _, item := range page.Feed {
+					render.FeedItem(item)
+					fmt.Println()
+				}
+				if page.Cursor != "" {
+					fmt.Println("Next page: yabc feed timeline --cursor", page.Cursor)
+				}
+				return
+			}
+
+			since, err := state.Since(token.DID, "timeline", firstRunWindow)
+			if err != nil {
+				slog.Error("Failed to read last run", "error", err)
+				fmt.Println("Error: Failed to read last run")
+				return
+			}
+
+			// Page back until we reach items we've already shown
+			var items []bluesky.FeedViewPost
+			cursor := ""
+			for pages := 0; pages < maxCatchUpPages; pages++ {
+				page, err := bluesky.GetTimeline(token, cursor, 100)
+				if err != nil {
+					slog.Error("Failed to get timeline", "error", err)
+					fmt.Println("Error: Failed to get timeline")
+					return
+				}
+
+				caughtUp := false
+				for _, item := range page.Feed {
+					if !state.Newer(item.IndexedAt(), since) {
+						caughtUp = true
+						break
+					}
+					items = append(items, item)
+				}
+				if caughtUp || page.Cursor == "" {
+					break
+				}
+				cursor = page.Cursor
+			}
+
+			if len(items) == 0 {
+				fmt.Println("Nothing new since", since.Local().Format(time.DateTime))
+				return
+			}
+
+			for _, item := range items {
+				render.FeedItem(item)
+				fmt.Println()
+			}
+
+			newest, err := time.Parse(time.RFC3339, items[0].IndexedAt())
+			if err != nil {
+				slog.Warn("Could not parse timestamp, last run not updated", "error", err)
+				return
+			}
+			if err := state.SetLastRun(token.DID, "timeline", newest); err != nil {
+				slog.Warn("Failed to save last run", "error", err)
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&timelineLimit, "limit", "l", 30, "Number of posts to show")
+	cmd.Flags().StringVar(&timelineCursor, "cursor", "", "Cursor of the page to show, as printed after the previous page")
+	cmd.Flags().BoolVar(&timelineSinceLastRun, "since-last-run", false, "Only show posts newer than the previous --since-last-run")
+
+	examples.SetValue(cmd, "limit", "10")
+	examples.Add(cmd, "", "limit")
+	examples.SetValue(cmd, "cursor", "2025-01-01T12:00:00.000Z::3k2a4b5c6d7e8")
+	examples.Add(cmd, "", "cursor")
+	examples.Add(cmd, "", "since-last-run")
+	examples.Add(cmd, "", "timezone", "time-format")
+
+	return cmd
+}
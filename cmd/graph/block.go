@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+func newBlockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "block <handle>",
+		Short: "Block an account",
+		Long: `Block an account, given by handle or DID. Neither of you can then see or
+interact with the other's posts.
+
+A block is a record in your repo, so like a follow it is public: anyone,
+including the blocked account, can see it. To quietly stop seeing an account
+instead, mute it. The URI of the block record is printed.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			block, err := bluesky.Block(token, args[0])
+			if errors.Is(err, bluesky.ErrAlreadyBlocked) {
+				fmt.Println("Already blocked:", block.URI)
+				return
+			}
+			if errors.Is(err, bluesky.ErrBlockSelf) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to block", "actor", args[0], "error", err)
+				fmt.Println("Error: Failed to block", args[0])
+				return
+			}
+
+			fmt.Println("Blocked:", block.URI)
+		},
+	}
+
+	examples.Add(cmd, "spammer.bsky.social")
+
+	return cmd
+}
+
+func newUnblockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unblock <handle>",
+		Short: "Unblock an account",
+		Long: `Unblock an account, given by handle or DID, deleting the block record
+found in your repo.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			uri, err := bluesky.Unblock(token, args[0])
+			if errors.Is(err, bluesky.ErrNotBlocked) {
+				fmt.Println("Not blocking", args[0])
+				return
+			}
+			if errors.Is(err, bluesky.ErrBlockSelf) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to unblock", "actor", args[0], "error", err)
+				fmt.Println("Error: Failed to unblock", args[0])
+				return
+			}
+
+			fmt.Println("Unblocked:", uri)
+		},
+	}
+
+	examples.Add(cmd, "spammer.bsky.social")
+
+	return cmd
+}
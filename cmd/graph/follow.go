@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+func newFollowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "follow <handle>",
+		Short: "Follow an account",
+		Long: `Follow an account, given by handle or DID. The URI of the follow record is
+printed. Following an account you already follow does nothing.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			follow, err := bluesky.Follow(token, args[0])
+			if errors.Is(err, bluesky.ErrAlreadyFollowing) {
+				fmt.Println("Already following:", follow.URI)
+				return
+			}
+			if errors.Is(err, bluesky.ErrFollowSelf) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to follow", "actor", args[0], "error", err)
+				fmt.Println("Error: Failed to follow", args[0])
+				return
+			}
+
+			fmt.Println("Followed:", follow.URI)
+		},
+	}
+
+	examples.Add(cmd, "alice.bsky.social")
+
+	return cmd
+}
+
+func newUnfollowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unfollow <handle>",
+		Short: "Unfollow an account",
+		Long: `Unfollow an account, given by handle or DID, deleting the follow record
+found in your repo.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			uri, err := bluesky.Unfollow(token, args[0])
+			if errors.Is(err, bluesky.ErrNotFollowing) {
+				fmt.Println("Not following", args[0])
+				return
+			}
+			if errors.Is(err, bluesky.ErrFollowSelf) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to unfollow", "actor", args[0], "error", err)
+				fmt.Println("Error: Failed to unfollow", args[0])
+				return
+			}
+
+			fmt.Println("Unfollowed:", uri)
+		},
+	}
+
+	examples.Add(cmd, "alice.bsky.social")
+
+	return cmd
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package graph
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listLimit int
+	listAll   bool
+	listYes   bool
+)
+
+// followList describes one of the two follow lists, so both commands share their code
+type followList struct {
+	use, short, noun string
+	fetch            func(token *bluesky.DIDResponse, actor, cursor string, limit int) ([]bluesky.ProfileViewBasic, string, error)
+	count            func(profile *bluesky.ProfileViewDetailed) int
+}
+
+func newFollowsCommand() *cobra.Command {
+	return newFollowListCommand(followList{
+		use:   "follows [handle]",
+		short: "List the accounts you, or someone else, follow",
+		noun:  "follows",
+		fetch: func(token *bluesky.DIDResponse, actor, cursor string, limit int) ([]bluesky.ProfileViewBasic, string, error) {
+			page, err := bluesky.GetFollows(token, actor, cursor, limit)
+			if err != nil {
+				return nil, "", err
+			}
+			return page.Follows, page.Cursor, nil
+		},
+		count: func(profile *bluesky.ProfileViewDetailed) int { return profile.FollowsCount },
+	})
+}
+
+func newFollowersCommand() *cobra.Command {
+	return newFollowListCommand(followList{
+		use:   "followers [handle]",
+		short: "List the accounts following you, or someone else",
+		noun:  "followers",
+		fetch: func(token *bluesky.DIDResponse, actor, cursor string, limit int) ([]bluesky.ProfileViewBasic, string, error) {
+			page, err := bluesky.GetFollowers(token, actor, cursor, limit)
+			if err != nil {
+				return nil, "", err
+			}
+			return page.Followers, page.Cursor, nil
+		},
+		count: func(profile *bluesky.ProfileViewDetailed) int { return profile.FollowersCount },
+	})
+}
+
+func newFollowListCommand(list followList) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   list.use,
+		Short: list.short,
+		Long: list.short + `.
+
+Only the first page is shown unless --all is given, which fetches the whole
+list (up to 10000 accounts), asking first when it is very long.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			actor := token.DID
+			if len(args) == 1 {
+				actor = args[0]
+			}
+
+			var accounts []bluesky.ProfileViewBasic
+			if !listAll {
+				accounts, _, err = list.fetch(token, actor, "", listLimit)
+			} else {
+				var profile *bluesky.ProfileViewDetailed
+				profile, err = bluesky.GetProfile(token, actor)
+				if err != nil {
+					slog.Error("Failed to get profile", "actor", actor, "error", err)
+					fmt.Println("Error: Failed to get profile of", actor)
+					return
+				}
+				if !listYes && !prompt.ConfirmLargeFetch(list.count(profile), list.noun) {
+					return
+				}
+
+				var capped bool
+				accounts, capped, err = bluesky.FetchAll(func(cursor string, limit int) ([]bluesky.ProfileViewBasic, string, error) {
+					return list.fetch(token, actor, cursor, limit)
+				})
+				if capped {
+					fmt.Printf("Warning: stopped after %d %s\n", len(accounts), list.noun)
+				}
+			}
+			if err != nil {
+				slog.Error("Failed to list "+list.noun, "actor", actor, "fetched", len(accounts), "error", err)
+				fmt.Println("Error: Failed to list", list.noun)
+				return
+			}
+
+			for _, account := range accounts {
+				fmt.Println(render.Author(account))
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&listLimit, "limit", "l", 50, "Number of accounts to show without --all")
+	cmd.Flags().BoolVar(&listAll, "all", false, "Fetch the whole list, following every page")
+	cmd.Flags().BoolVarP(&listYes, "yes", "y", false, "Don't ask before fetching a very long list")
+
+	examples.Add(cmd, "")
+	examples.Add(cmd, "alice.bsky.social", "all")
+
+	return cmd
+}
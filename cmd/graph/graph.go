@@ -0,0 +1,21 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package graph
+
+import "github.com/spf13/cobra"
+
+func NewGraphCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Explore who follows whom on Bluesky, and follow, block or mute accounts",
+	}
+	cmd.AddCommand(newFollowsCommand())
+	cmd.AddCommand(newFollowersCommand())
+	cmd.AddCommand(newFollowCommand())
+	cmd.AddCommand(newUnfollowCommand())
+	cmd.AddCommand(newBlockCommand())
+	cmd.AddCommand(newUnblockCommand())
+	cmd.AddCommand(newMuteCommand())
+	cmd.AddCommand(newUnmuteCommand())
+
+	return cmd
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+// muteAction describes muting or unmuting, so both commands share their code
+type muteAction struct {
+	use, short, long string
+	verb, done       string
+	apply            func(token *bluesky.DIDResponse, actor string) (string, error)
+}
+
+func newMuteCommand() *cobra.Command {
+	return newMuteActionCommand(muteAction{
+		use:   "mute <handle>",
+		short: "Mute an account",
+		long: `Mute an account, given by handle or DID, to stop seeing its posts in your
+feeds and its notifications. It can still see and reply to your posts.
+
+Unlike a block, a mute isn't a record in your repo: Bluesky keeps it
+privately, and nobody else, including the muted account, can see it.`,
+		verb:  "mute",
+		done:  "Muted",
+		apply: bluesky.Mute,
+	})
+}
+
+func newUnmuteCommand() *cobra.Command {
+	return newMuteActionCommand(muteAction{
+		use:   "unmute <handle>",
+		short: "Unmute an account",
+		long:  `Unmute an account, given by handle or DID. Unmuting an account that isn't muted does nothing.`,
+		verb:  "unmute",
+		done:  "Unmuted",
+		apply: bluesky.Unmute,
+	})
+}
+
+func newMuteActionCommand(action muteAction) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   action.use,
+		Short: action.short,
+		Long:  action.long,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			did, err := action.apply(token, args[0])
+			if errors.Is(err, bluesky.ErrMuteSelf) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to "+action.verb, "actor", args[0], "error", err)
+				fmt.Println("Error: Failed to", action.verb, args[0])
+				return
+			}
+
+			fmt.Printf("%s: %s (%s)\n", action.done, args[0], did)
+		},
+	}
+
+	examples.Add(cmd, "loud.bsky.social")
+
+	return cmd
+}
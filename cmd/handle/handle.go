@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package handle
+
+import "github.com/spf13/cobra"
+
+func NewHandleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "handle",
+		Short: "Manage your account's handle",
+	}
+	cmd.AddCommand(newSetCommand())
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package handle
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+func newSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <new-handle>",
+		Short: "Change your account's handle",
+		Long: `Change your account's handle, e.g. to move to a custom domain.
+
+A custom domain must first point at your DID, through either a DNS TXT record
+"_atproto.<domain>" containing "did=<your did>", or a file served at
+https://<domain>/.well-known/atproto-did containing your DID.
+
+Links that use your old handle may stop working.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			newHandle := strings.ToLower(strings.TrimPrefix(args[0], "@"))
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			oldHandle := token.Handle
+			if newHandle == oldHandle {
+				fmt.Printf("Your handle is already %s\n", newHandle)
+				return
+			}
+
+			err = bluesky.UpdateHandle(token, newHandle)
+			if errors.Is(err, bluesky.ErrHandleTaken) || errors.Is(err, bluesky.ErrHandleVerification) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to update handle", "handle", newHandle, "error", err)
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Refresh the stored session so it carries the new handle
+			session, err := bluesky.RefreshToken(token)
+			if err != nil {
+				slog.Warn("Failed to refresh session", "error", err)
+			} else if err := bluesky.SaveSession(session); err != nil {
+				slog.Warn("Failed to save session", "error", err)
+			}
+
+			fmt.Printf("Handle changed from %s to %s\n", oldHandle, newHandle)
+			fmt.Printf("Warning: links using @%s may no longer work\n", oldHandle)
+		},
+	}
+
+	examples.Add(cmd, "alice.example.com")
+
+	return cmd
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package notifications
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/alexisbcz/yabc/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// firstRunWindow is how far back --since-last-run looks the first time
+	firstRunWindow = 24 * time.Hour
+	// maxCatchUpPages bounds how many pages --since-last-run fetches to catch up
+	maxCatchUpPages = 10
+)
+
+var (
+	limit        int
+	sinceLastRun bool
+	unreadOnly   bool
+)
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your notifications",
+		Long: `List your likes, reposts, follows, mentions, replies and quotes, newest first.
+
+With --since-last-run, only notifications that arrived since the previous
+--since-last-run are shown (the last 24 hours on the first run).
+
+With --unread-only, only notifications that arrived since they were last
+marked as read, in any app or with "yabc notifications mark-read", are shown.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if sinceLastRun && unreadOnly {
+				fmt.Println("Error: --since-last-run and --unread-only can't be used together")
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			if unreadOnly {
+				notifications, err := listUnread(token)
+				if err != nil {
+					slog.Error("Failed to list notifications", "error", err)
+					fmt.Println("Error: Failed to list notifications")
+					return
+				}
+				if len(notifications) == 0 {
+					fmt.Println("No unread notifications")
+					return
+				}
+				for _, notification := range notifications {
+					render.Notification(notification)
+					fmt.Println()
+				}
+				return
+			}
+
+			if !sinceLastRun {
+				page, err := bluesky.ListNotifications(token, "", limit)
+				if err != nil {
+					slog.Error("Failed to list notifications", "error", err)
+					fmt.Println("Error: Failed to list notifications")
+					return
+				}
+				for _, notification := range page.Notifications {
+					render.Notification(notification)
+					fmt.Println()
+				}
+				return
+			}
+
+			since, err := state.Since(token.DID, "notifications", firstRunWindow)
+			if err != nil {
+				slog.Error("Failed to read last run", "error", err)
+				fmt.Println("Error: Failed to read last run")
+				return
+			}
+
+			// Page back until we reach notifications we've already shown
+			var notifications []bluesky.Notification
+			cursor := ""
+			for pages := 0; pages < maxCatchUpPages; pages++ {
+				page, err := bluesky.ListNotifications(token, cursor, 100)
+				if err != nil {
+					slog.Error("Failed to list notifications", "error", err)
+					fmt.Println("Error: Failed to list notifications")
+					return
+				}
+
+				caughtUp := false
+				for _, notification := range page.Notifications {
+					if !state.Newer(notification.IndexedAt, since) {
+						caughtUp = true
+						break
+					}
+					notifications = append(notifications, notification)
+				}
+				if caughtUp || page.Cursor == "" {
+					break
+				}
+				cursor = page.Cursor
+			}
+
+			if len(notifications) == 0 {
+				fmt.Println("Nothing new since", since.Local().Format(time.DateTime))
+				return
+			}
+
+			for _, notification := range notifications {
+				render.Notification(notification)
+				fmt.Println()
+			}
+
+			newest, err := time.Parse(time.RFC3339, notifications[0].IndexedAt)
+			if err != nil {
+				slog.Warn("Could not parse timestamp, last run not updated", "error", err)
+				return
+			}
+			if err := state.SetLastRun(token.DID, "notifications", newest); err != nil {
+				slog.Warn("Failed to save last run", "error", err)
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 30, "Number of notifications to show")
+	cmd.Flags().BoolVar(&unreadOnly, "unread-only", false, "Only show notifications not marked as read")
+	cmd.Flags().BoolVar(&sinceLastRun, "since-last-run", false, "Only show notifications newer than the previous --since-last-run")
+
+	examples.SetValue(cmd, "limit", "10")
+	examples.Add(cmd, "", "limit")
+	examples.Add(cmd, "", "since-last-run")
+	examples.Add(cmd, "", "unread-only")
+	examples.Add(cmd, "", "timezone", "time-format")
+
+	return cmd
+}
+
+// listUnread pages back through notifications until it reaches ones
+// already read, which are older than the seenAt boundary
+func listUnread(token *bluesky.DIDResponse) ([]bluesky.Notification, error) {
+	var unread []bluesky.Notification
+	cursor := ""
+	for pages := 0; pages < maxCatchUpPages; pages++ {
+		page, err := bluesky.ListNotifications(token, cursor, 100)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, notification := range page.Notifications {
+			if notification.IsRead {
+				return unread, nil
+			}
+			unread = append(unread, notification)
+		}
+		if page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return unread, nil
+}
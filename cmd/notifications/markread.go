@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package notifications
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+func newMarkReadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mark-read",
+		Short: "Mark all your notifications as read",
+		Long: `Mark every notification received so far as read, in yabc and in every other
+Bluesky app.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			if err := bluesky.UpdateSeen(token, time.Now()); err != nil {
+				slog.Error("Failed to mark notifications as read", "error", err)
+				fmt.Println("Error: Failed to mark notifications as read")
+				return
+			}
+
+			fmt.Println("Notifications marked as read")
+		},
+	}
+
+	examples.Add(cmd, "")
+
+	return cmd
+}
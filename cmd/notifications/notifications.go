@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package notifications
+
+import (
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+func NewNotificationsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notifications",
+		Short: "Read your notifications",
+	}
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newMarkReadCommand())
+	render.AddTimeFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clearLikesYes bool
+)
+
+func newClearLikesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear-likes",
+		Short: "Unlike every post you have liked",
+		Long: `Unlike every post you have liked, by deleting the like records in your repo.
+
+Deletions are paced to stay under the PDS's rate limits, so clearing thousands
+of likes takes a while. Up to 10,000 likes are cleared per run. It is safe to
+interrupt: run the command again to pick up where it left off.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			fmt.Println("Counting likes...")
+			likes, capped, err := bluesky.ListLikeRecords(token)
+			if err != nil {
+				slog.Error("Failed to list likes", "error", err)
+				fmt.Println("Error: Failed to list likes")
+				return
+			}
+			if len(likes) == 0 {
+				fmt.Println("No likes to clear.")
+				return
+			}
+
+			count := fmt.Sprint(len(likes))
+			if capped {
+				count = fmt.Sprintf("the latest %d", len(likes))
+			}
+			if !clearLikesYes {
+				duration := (time.Duration(len(likes)) * bluesky.DeletePacing).Round(time.Minute)
+				description := fmt.Sprintf("This can't be undone and will take about %s.", max(duration, time.Minute))
+				if !prompt.Confirm(fmt.Sprintf("Unlike %s posts?", count), description) {
+					fmt.Println("Cancelled.")
+					return
+				}
+			}
+
+			uris := make([]string, len(likes))
+			for i, like := range likes {
+				uris[i] = like.URI
+			}
+
+			deleted, err := bluesky.DeleteRecords(token, uris, func(deleted int) {
+				fmt.Printf("\rUnliked %d of %d", deleted, len(uris))
+			})
+			fmt.Println()
+			if errors.Is(err, bluesky.ErrRateLimited) {
+				fmt.Printf("Rate limited after %d likes. Run clear-likes again later to continue.\n", deleted)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to clear likes", "deleted", deleted, "error", err)
+				fmt.Printf("Error: Failed to clear likes after %d. Run clear-likes again to continue.\n", deleted)
+				return
+			}
+
+			fmt.Printf("Unliked %d posts.\n", deleted)
+			if capped {
+				fmt.Println("More likes remain, run clear-likes again to continue.")
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&clearLikesYes, "yes", "y", false, "Don't ask for confirmation")
+
+	examples.Add(cmd, "")
+	examples.Add(cmd, "", "yes")
+
+	return cmd
+}
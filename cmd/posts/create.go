@@ -2,27 +2,68 @@
 package posts
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	_ "image/gif"  // Support gif format
-	_ "image/jpeg" // Support jpeg format
-	_ "image/png"  // Support png format
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/config"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/alexisbcz/yabc/internal/richtext"
+	"github.com/alexisbcz/yabc/internal/state"
+	"github.com/alexisbcz/yabc/internal/ui"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 )
 
 var (
-	API_URL = "https://bsky.social/xrpc"
-)
+	text        string
+	hashtags    []string
+	imageFiles  []string
+	alts        []string
+	videoFile   string
+	videoAlt    string
+	gifURL      string
+	gifAlt      string
+	card        string
+	cardNoThumb bool
+	replyAllow  []string
+	replyTo     string
+	place       string
+	coords      string
+	blurhash    bool
+	autoThread  bool
 
-var (
-	text      string
-	hashtags  []string
-	imageFile string
+	imageDir        string
+	altFromFilename bool
+	altFromSidecar  bool
+	dedupeImages    bool
+	maxDimension    int
+	imageQuality    int
+	compress        bool
+	maxLength       int
+	langs           []string
+	labels          []string
+	linkify         bool
+	warnMissingAlt  bool
+	stripMetadata   bool
+	expandEmoji     bool
+	strict          bool
+	printURI        bool
+	createJSON      bool
+	dryRun          bool
+	templateName    string
+	templateVars    []string
+	scheduleAt      string
+	createdAt       string
 )
 
 func newCreatePostCommand() *cobra.Command {
@@ -30,16 +71,204 @@ func newCreatePostCommand() *cobra.Command {
 		Use:   "create",
 		Short: "Create a new post on Bluesky",
 		Long: `Create a new post on the Bluesky social network.
-		
-You can include text content, hashtags, and optionally attach an image.
-		
-Example usage:
-    yabc posts create
-	yabc posts create --text "Hello world!" --hashtags coding,golang
-	yabc posts create --text "Check out this photo" --image path/to/image.jpg`,
-		Run: func(cmd *cobra.Command, args []string) {
-			if text == "" && imageFile == "" {
-				var hashtagInput string
+
+You can include text content, hashtags, and optionally attach up to 4 images,
+a video, a Tenor GIF or a link card. Without --text, --image, --video, --gif
+or --card, an interactive form is shown.
+
+With --auto-thread, text longer than a post is split at sentence or word
+boundaries into a thread, each part replying to the previous one. Attachments
+go on the first post.
+
+With --template, the text comes from a named template in the [templates]
+section of ~/.config/yabc/config.toml, filled in with --var key=value:
+
+  [templates]
+  daily = "Day {{.day}} of {{.project}}: {{.note}}"
+
+An interrupted video post can be retried with the same file: the video is not
+uploaded again, and yabc waits on the processing job already started.
+
+With --at, the post is queued on this computer instead of posted, and
+"yabc posts flush" posts it once that time has passed. Run flush from cron or
+a systemd timer to post on time.
+
+With --created-at, the post is backdated to that time, e.g. when bringing over
+posts from elsewhere. It is posted now, but sorted by that time in feeds.`,
+		// Errors are reported by createOutput, as text or JSON, and only set the exit status
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newCreateOutput(cmd.OutOrStdout(), createJSON, dryRun)
+
+			if cmd.Flags().Changed("max-length") && maxLength < 1 {
+				return out.failUsage(fmt.Sprintf("invalid --max-length %d: must be at least 1", maxLength))
+			}
+
+			// Validate reply rules up front so we never post without the requested gate
+			if _, err := bluesky.ParseReplyRules(replyAllow); err != nil {
+				return out.fail(err.Error())
+			}
+			if len(replyAllow) > 0 && replyTo != "" {
+				return out.fail(bluesky.ErrThreadgateOnReply.Error())
+			}
+
+			// A scheduled post is only checked now and posted by "yabc posts flush"
+			var at time.Time
+			if scheduleAt != "" {
+				if dryRun || createJSON {
+					return out.fail("--at can't be used with --dry-run or --json")
+				}
+				var err error
+				if at, err = time.Parse(time.RFC3339, scheduleAt); err != nil {
+					return out.fail(fmt.Sprintf("invalid --at %q: expected a time like 2025-06-01T09:00:00Z", scheduleAt))
+				}
+				if at.Before(time.Now()) {
+					return out.fail(fmt.Sprintf("--at %s is in the past", scheduleAt))
+				}
+			}
+
+			// A backdated post keeps its original time, which can't be in the future
+			var postCreatedAt time.Time
+			if createdAt != "" {
+				if scheduleAt != "" {
+					return out.fail("--created-at can't be used with --at")
+				}
+				var err error
+				if postCreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+					return out.fail(fmt.Sprintf("invalid --created-at %q: expected a time like 2019-03-14T15:09:26Z", createdAt))
+				}
+				if err := bluesky.ValidateCreatedAt(postCreatedAt); err != nil {
+					return out.fail(err.Error())
+				}
+			}
+
+			// Fill in the template, if any, before anything else looks at the text
+			if templateName != "" {
+				if text != "" {
+					return out.fail("--template and --text can't be used together")
+				}
+
+				vars, err := config.ParseVars(templateVars)
+				if err != nil {
+					return out.fail(err.Error())
+				}
+				if text, err = config.Current().RenderTemplate(templateName, vars); err != nil {
+					return out.fail(err.Error())
+				}
+			} else if len(templateVars) > 0 {
+				return out.fail("--var requires --template")
+			}
+
+			// Validate the location before anything is posted
+			var postPlace *bluesky.Place
+			if place != "" {
+				var err error
+				if postPlace, err = bluesky.ParsePlace(place, coords); err != nil {
+					return out.fail(err.Error())
+				}
+			} else if coords != "" {
+				return out.fail("--coords requires --place")
+			}
+
+			// Make sure the post being replied to still exists before composing anything
+			var reply *bluesky.ReplyRef
+			if replyTo != "" && dryRun {
+				reply = bluesky.DryRunReplyRef(replyTo)
+			} else if replyTo != "" && scheduleAt == "" {
+				// A scheduled post looks up the post it replies to when it is posted
+				parentURI, err := bluesky.ResolveURI(replyTo)
+				if err != nil {
+					return out.fail(err.Error())
+				}
+
+				reply, err = bluesky.ResolveReplyRef(parentURI)
+				if errors.Is(err, bluesky.ErrReplyParentNotFound) || errors.Is(err, bluesky.ErrReplyRootNotFound) {
+					return out.fail(err.Error())
+				}
+				if err != nil {
+					slog.Error("Failed to resolve reply target", "uri", parentURI, "error", err)
+					return out.fail("Failed to resolve the post to reply to")
+				}
+			}
+
+			// Attach the images of a directory after any given with --image
+			if imageDir != "" {
+				dirImages, err := bluesky.ImagesInDir(imageDir)
+				if err != nil {
+					return out.fail(err.Error())
+				}
+				if total := len(imageFiles) + len(dirImages); total > bluesky.MaxImages {
+					dropped := dirImages[max(bluesky.MaxImages-len(imageFiles), 0):]
+					return out.fail(fmt.Sprintf("too many images: %d with --image-dir (%d maximum), these would be dropped: %s", total, bluesky.MaxImages, strings.Join(dropped, ", ")))
+				}
+				imageFiles = append(imageFiles, dirImages...)
+			}
+
+			// Catch too many images before any of them is uploaded
+			if len(imageFiles) > bluesky.MaxImages {
+				return out.fail(fmt.Sprintf("too many images: %d (%d maximum)", len(imageFiles), bluesky.MaxImages))
+			}
+
+			embeds := 0
+			for _, set := range []bool{len(imageFiles) > 0, videoFile != "", gifURL != "", card != ""} {
+				if set {
+					embeds++
+				}
+			}
+			if embeds > 1 {
+				return out.fail("a post can have images, a video, a GIF or a link card, but only one of them")
+			}
+			if gifURL != "" && !bluesky.IsTenorURL(gifURL) {
+				return out.fail(fmt.Sprintf("--gif must be a Tenor GIF, e.g. https://tenor.com/view/... or https://media.tenor.com/.../name.gif, not %s", gifURL))
+			}
+			if gifAlt != "" && gifURL == "" {
+				return out.fail("--gif-alt requires --gif")
+			}
+			if cardNoThumb && card == "" {
+				return out.fail("--embed-external-no-thumb requires --card")
+			}
+			// Posts are in the languages from the config file, or else the user's locale, unless told otherwise
+			if len(langs) == 0 {
+				langs = config.Current().Langs
+			}
+			if len(langs) == 0 {
+				langs = bluesky.DefaultLangs()
+			}
+			if !cmd.Flags().Changed("linkify") {
+				linkify = config.Current().LinkifyEnabled()
+			}
+			if !cmd.Flags().Changed("warn-missing-alt") {
+				warnMissingAlt = config.Current().WarnMissingAltEnabled()
+			}
+			if !cmd.Flags().Changed("strip-metadata") {
+				stripMetadata = config.Current().StripMetadataEnabled()
+			}
+			if !cmd.Flags().Changed("expand-emoji") {
+				expandEmoji = config.Current().ExpandEmojiEnabled()
+			}
+			postLangs, err := bluesky.ValidateLangs(langs)
+			if err != nil {
+				return out.fail(err.Error())
+			}
+
+			postLabels, err := bluesky.ValidateSelfLabels(labels)
+			if err != nil {
+				return out.fail(err.Error())
+			}
+
+			// The quality is only checked against --max-dimension and --compress when given
+			if !cmd.Flags().Changed("image-quality") {
+				imageQuality = 0
+			}
+			imageOptions := bluesky.ImageOptions{MaxDimension: maxDimension, Quality: imageQuality, Compress: compress, StripMetadata: stripMetadata}
+			if err := imageOptions.Validate(); err != nil {
+				return out.fail(err.Error())
+			}
+
+			limit := postLengthLimit(maxLength)
+			if text == "" && embeds == 0 {
+				var hashtagInput, imageFile string
 
 				// Create a form with text and hashtags
 				form := huh.NewForm(
@@ -47,6 +276,15 @@ Example usage:
 						huh.NewInput().
 							Title("Type text content for the post").
 							Placeholder("Hello world!").
+							DescriptionFunc(func() string {
+								return fmt.Sprintf("%d/%d graphemes", bluesky.PostLength(text), limit)
+							}, &text).
+							Validate(func(s string) error {
+								if length := bluesky.PostLength(s); length > limit && !autoThread {
+									return fmt.Errorf("post is %d graphemes, maximum is %d", length, limit)
+								}
+								return nil
+							}).
 							Value(&text),
 						huh.NewInput().
 							Title("Add hashtags (comma-separated)").
@@ -56,13 +294,28 @@ Example usage:
 							Title("Select an image (optional)").
 							Picking(true).
 							Value(&imageFile).
-							AllowedTypes([]string{".jpg", ".jpeg", ".png", ".gif"}),
+							AllowedTypes([]string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic", ".heif"}),
 					),
-				)
+				).WithOutput(out.prompts)
 
 				if err := form.Run(); err != nil {
-					slog.Error("Failed to get user input", "error", err)
-					os.Exit(1)
+					return out.formFailed(err)
+				}
+
+				if imageFile != "" {
+					imageFiles = append(imageFiles, imageFile)
+
+					// Ask for the picked image's alt text, for people using screen readers
+					var alt string
+					err := huh.NewForm(huh.NewGroup(huh.NewInput().
+						Title("Describe the image (alt text)").
+						Placeholder("A cat sleeping on a keyboard").
+						Value(&alt),
+					)).WithOutput(out.prompts).Run()
+					if err != nil {
+						return out.formFailed(err)
+					}
+					alts = append(alts, strings.TrimSpace(alt))
 				}
 
 				// Process hashtags
@@ -80,29 +333,518 @@ Example usage:
 				content += fmt.Sprintf(" #%s", tag)
 			}
 
+			// Expand shortcodes first, so facets and the length are worked out on the text as posted
+			if expandEmoji {
+				content = richtext.ExpandEmoji(content)
+			}
+
+			// A form submitted blank, or blank --text, would only be rejected by Bluesky
+			if strings.TrimSpace(content) == "" && len(imageFiles) == 0 && videoFile == "" && gifURL == "" && card == "" {
+				return out.failUsage("nothing to post: give some text, an image, a video, a GIF or a link card")
+			}
+
+			// Catch text that's too long before uploading anything
+			if length := bluesky.PostLength(content); length > limit && !autoThread {
+				return out.fail(fmt.Sprintf("post is %d graphemes, maximum is %d (use --auto-thread to post a thread)", length, limit))
+			}
+
+			// Catch mistyped mentions, which would be posted as plain text. A dry run
+			// makes no network calls, so can't check them.
+			if !dryRun {
+				unresolved := bluesky.UnresolvedMentions(content)
+				if len(unresolved) > 0 && strict {
+					return out.fail(fmt.Sprintf("mentioned handles don't resolve to an account: @%s", strings.Join(unresolved, ", @")))
+				}
+				warnUnresolvedMentions(unresolved)
+			}
+
+			// Each --alt describes the image at the same position
+			if len(alts) > len(imageFiles) {
+				return out.fail(fmt.Sprintf("--alt given %d times for %d images", len(alts), len(imageFiles)))
+			}
+			var images []bluesky.ImageAttachment
+			missingAlt := 0
+			for i, imageFile := range imageFiles {
+				image := bluesky.ImageAttachment{Path: imageFile}
+				if i < len(alts) {
+					image.Alt = alts[i]
+				}
+
+				// Read alt text from a .txt file next to images not described with --alt
+				if image.Alt == "" && altFromSidecar {
+					alt, err := bluesky.AltFromSidecar(imageFile)
+					if err != nil {
+						return out.fail(err.Error())
+					}
+					image.Alt = alt
+				}
+
+				// Use the caption embedded in the photo, as photographers often add one
+				if image.Alt == "" {
+					if image.Alt = bluesky.AltFromMetadata(imageFile); image.Alt != "" {
+						ui.Info("Using the caption of %s as alt text", imageFile)
+					}
+				}
+
+				// Derive alt text from the file name for images not described otherwise
+				if image.Alt == "" && altFromFilename {
+					image.Alt = bluesky.AltFromFilename(imageFile)
+				}
+
+				if image.Alt == "" {
+					missingAlt++
+				}
+				images = append(images, image)
+			}
+			if missingAlt > 0 && warnMissingAlt && !confirmMissingAlt(missingAlt) {
+				return out.fail("cancelled: add alt text with --alt")
+			}
+
+			// Print each image's blurhash for people building custom feeds or records
+			if blurhash {
+				for _, imageFile := range imageFiles {
+					hash, err := bluesky.ImageBlurhash(imageFile)
+					if err != nil {
+						slog.Warn("Could not compute blurhash", "path", imageFile, "error", err)
+						continue
+					}
+					ui.Info("Blurhash for %s: %s", imageFile, hash)
+				}
+			}
+
+			opts := bluesky.PostOptions{
+				Reply:        reply,
+				Place:        postPlace,
+				DedupeImages: dedupeImages,
+				ImageOptions: imageOptions,
+				MaxGraphemes: limit,
+				Langs:        postLangs,
+				Labels:       postLabels,
+				NoLinkify:    !linkify,
+				Card:         card,
+				CardNoThumb:  cardNoThumb,
+				CreatedAt:    postCreatedAt,
+			}
+			opts.Images = images
+			if videoFile != "" {
+				opts.Video = &bluesky.VideoAttachment{Path: videoFile, Alt: videoAlt}
+			}
+			if gifURL != "" {
+				opts.GIF = &bluesky.GIFAttachment{URL: gifURL, Alt: gifAlt}
+			}
+
+			// Split long text into a thread, keeping URLs whole and clickable in each part
+			parts := []richtext.Text{{Text: content}}
+			if autoThread && bluesky.PostLength(content) > limit {
+				parts = richtext.Split(richtext.WithLinks(richtext.Text{Text: content}), limit)
+				ui.Info("Text is too long for one post, posting a thread of %d parts", len(parts))
+
+				// Splitting still keeps URLs whole, they just don't become links
+				if !linkify {
+					for i := range parts {
+						parts[i].Links = nil
+					}
+				}
+			}
+
+			// Queue the post instead of posting it
+			if scheduleAt != "" {
+				return queuePost(out, state.ScheduledPost{
+					At:           at,
+					Profile:      profile.Active(),
+					Text:         content,
+					Video:        videoFile,
+					VideoAlt:     videoAlt,
+					GIF:          gifURL,
+					GIFAlt:       gifAlt,
+					Card:         card,
+					NoThumb:      cardNoThumb,
+					ReplyTo:      replyTo,
+					ReplyAllow:   replyAllow,
+					Langs:        postLangs,
+					Labels:       postLabels,
+					NoLinkify:    !linkify,
+					Place:        place,
+					Coords:       coords,
+					AutoThread:   autoThread,
+					MaxLength:    postLengthLimit(maxLength),
+					DedupeImages: dedupeImages,
+					MaxDimension: maxDimension,
+					Quality:      imageQuality,
+					Compress:     compress,
+					KeepMetadata: !stripMetadata,
+				}, images)
+			}
+
+			// Show the records instead of posting them
+			if dryRun {
+				return out.dryRun(parts, opts)
+			}
+
 			// Get authentication token
 			token, err := bluesky.GetToken()
 			if err != nil {
 				slog.Error("Failed to get authentication token", "error", err)
-				fmt.Println("Error: Failed to authenticate with Bluesky")
-				return
+				return out.fail("Failed to authenticate with Bluesky")
 			}
 
-			// Create the post
-			err = bluesky.CreatePost(token, content, imageFile)
+			// Create the post, or the thread starting with it
+			posts, err := bluesky.CreateThread(token, parts, opts)
 			if err != nil {
 				slog.Error("Failed to create post", "error", err)
-				fmt.Println("Error: Failed to create post")
-				return
+				if len(posts) > 0 {
+					return out.fail(fmt.Sprintf("Failed to create post, %d of %d parts already posted, starting at %s", len(posts), len(parts), posts[0].URI))
+				}
+				// Show what Bluesky said, e.g. "InvalidToken: Token has expired"
+				var xrpcErr *bluesky.XRPCError
+				if errors.As(err, &xrpcErr) {
+					return out.fail("Failed to create post: " + xrpcErr.Error())
+				}
+				return out.fail("Failed to create post")
+			}
+			postResp := posts[0]
+
+			// Restrict who can reply, if requested
+			if len(replyAllow) > 0 {
+				if err := bluesky.CreateThreadgate(token, postResp.URI, replyAllow); err != nil {
+					slog.Error("Failed to restrict replies", "uri", postResp.URI, "error", err)
+					ui.Warn("Post created, but replies could not be restricted")
+				}
 			}
 
-			fmt.Println("Post created successfully!")
+			if createJSON {
+				out.success(posts)
+				return nil
+			}
+
+			ui.Success("Post created successfully!")
+
+			// Print what scripts need to reply to, like or delete the post
+			if printURI {
+				for _, post := range posts {
+					fmt.Println("URI:", post.URI)
+					fmt.Println("CID:", post.CID)
+				}
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&text, "text", "t", "", "Text content for the post")
 	cmd.Flags().StringSliceVarP(&hashtags, "hashtags", "a", []string{}, "Comma-separated list of hashtags (without # symbol)")
-	cmd.Flags().StringVarP(&imageFile, "image", "i", "", "Path to image file to attach to the post")
+	cmd.Flags().StringVar(&templateName, "template", "", "Name of a template from the config file to use as the text")
+	cmd.Flags().StringArrayVar(&templateVars, "var", []string{}, "Template variable as key=value (repeat for each variable)")
+	cmd.Flags().StringArrayVarP(&imageFiles, "image", "i", []string{}, "Path or http(s) URL of an image to attach (repeat for up to 4 images)")
+	cmd.Flags().StringVar(&videoFile, "video", "", "Path to an MP4 video to attach")
+	cmd.Flags().StringVar(&gifURL, "gif", "", "Tenor GIF to attach, as a tenor.com/view page or media.tenor.com URL")
+	cmd.Flags().StringVar(&gifAlt, "gif-alt", "", "Alt text for the GIF")
+	cmd.Flags().StringVar(&videoAlt, "video-alt", "", "Alt text for the video")
+	cmd.Flags().StringVar(&card, "card", "", "URL to show as a link card, with the page's title, description and image")
+	cmd.Flags().BoolVar(&cardNoThumb, "embed-external-no-thumb", false, "Build the link card without fetching and uploading its image")
+	cmd.Flags().BoolVar(&dedupeImages, "dedupe-images", false, "Drop images attached more than once instead of warning")
+	cmd.Flags().IntVar(&maxDimension, "max-dimension", 0, "Downscale images whose width or height exceeds this many pixels, re-encoding them as JPEG")
+	cmd.Flags().IntVar(&imageQuality, "image-quality", bluesky.DefaultImageQuality, "JPEG quality (1-100) of images resized with --max-dimension or compressed with --compress")
+	cmd.Flags().StringArrayVar(&alts, "alt", []string{}, "Alt text describing an image (repeat for each --image, in the same order)")
+	cmd.Flags().BoolVar(&compress, "compress", false, "Re-encode images over 1MB as JPEG, lowering quality and size until they fit")
+	cmd.Flags().BoolVar(&expandEmoji, "expand-emoji", false, "Turn shortcodes like :rocket: into emoji (default from expand_emoji in the config file)")
+	cmd.Flags().BoolVar(&stripMetadata, "strip-metadata", true, "Remove EXIF, GPS and other metadata from JPEG and PNG images before upload (default from strip_metadata in the config file)")
+	cmd.Flags().BoolVar(&altFromFilename, "alt-from-filename", false, "Derive alt text from the image file name")
+	cmd.Flags().StringVar(&imageDir, "image-dir", "", `Attach the images in a directory, or matching a pattern like "photos/*.jpg", sorted by file name`)
+	cmd.Flags().BoolVar(&altFromSidecar, "alt-from-sidecar", false, `Read alt text from a .txt file next to each image, e.g. "heron.txt" for "heron.jpg"`)
+	cmd.Flags().BoolVar(&blurhash, "blurhash", false, "Print the blurhash of each attached image")
+	cmd.Flags().StringVar(&place, "place", "", `Location to add to the post, e.g. "Paris, France" (non-standard)`)
+	cmd.Flags().StringVar(&coords, "coords", "", "Coordinates of --place as latitude,longitude, stored in a custom field")
+	cmd.Flags().BoolVar(&autoThread, "auto-thread", false, "Split text longer than a post into a thread")
+	cmd.Flags().IntVar(&maxLength, "max-length", 0, "Most graphemes (characters as displayed, an emoji counting once) allowed in a post (default: 300, Bluesky's limit)")
+	cmd.Flags().StringArrayVar(&langs, "lang", []string{}, "BCP-47 code of the post's language, e.g. en (repeat for up to 3, default: from the config file or $LANG)")
+	cmd.Flags().BoolVar(&linkify, "linkify", true, "Turn bare URLs into links (default from linkify in the config file)")
+	cmd.Flags().BoolVar(&warnMissingAlt, "warn-missing-alt", true, "Ask before posting images without alt text (default from warn_missing_alt in the config file)")
+	cmd.Flags().StringArrayVar(&labels, "label", []string{}, "Content warning for the post: sexual, nudity, porn or graphic-media (repeatable)")
+	cmd.Flags().StringVarP(&replyTo, "reply-to", "r", "", "URI or bsky.app link of the post to reply to")
+	cmd.Flags().BoolVar(&createJSON, "json", false, "Print the created post, or the error, as a JSON object and nothing else on stdout")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the records that would be posted, without posting or uploading anything")
+	cmd.Flags().BoolVar(&printURI, "print-uri", false, "Print the URI and CID of the created post (of each post of a thread)")
+	cmd.Flags().StringVar(&scheduleAt, "at", "", `Queue the post for "yabc posts flush" to post after this RFC 3339 time, e.g. 2025-06-01T09:00:00Z`)
+	cmd.Flags().StringVar(&createdAt, "created-at", "", "Backdate the post to this RFC 3339 time, e.g. 2019-03-14T15:09:26Z")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Don't post if a mentioned handle doesn't resolve to an account")
+	cmd.Flags().StringSliceVar(&replyAllow, "reply-allow", []string{}, "Who can reply: any combination of mentioned,following,followers, or nobody")
+
+	examples.SetValue(cmd, "text", "Hello world!")
+	examples.SetValue(cmd, "hashtags", "coding,golang")
+	examples.SetValue(cmd, "image", "first.jpg", "second.jpg")
+	examples.SetValue(cmd, "reply-allow", "mentioned,following")
+	examples.SetValue(cmd, "reply-to", "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+	examples.Add(cmd, "")
+	examples.Add(cmd, "", "text", "hashtags")
+	examples.Add(cmd, "", "text", "print-uri")
+	examples.Add(cmd, "", "text", "json")
+	examples.Add(cmd, "", "text", "dry-run")
+	examples.SetValue(cmd, "at", "2025-06-01T09:00:00Z")
+	examples.Add(cmd, "", "text", "at")
+	examples.Add(cmd, "", "text", "image", "at")
+	examples.SetValue(cmd, "created-at", "2019-03-14T15:09:26Z")
+	examples.Add(cmd, "", "text", "created-at")
+	examples.SetValue(cmd, "lang", "en", "fr")
+	examples.Add(cmd, "", "text", "lang")
+	examples.SetValue(cmd, "label", "graphic-media")
+	examples.Add(cmd, "", "text", "image", "label")
+	examples.SetValue(cmd, "alt", "My cat asleep on the sofa", "The same cat, now awake")
+	examples.Add(cmd, "", "text", "image", "alt")
+	examples.Add(cmd, "", "text", "image", "alt-from-filename")
+	examples.SetValue(cmd, "image-dir", "./photos")
+	examples.Add(cmd, "", "text", "image-dir", "alt-from-sidecar")
+	examples.Add(cmd, "", "text", "image", "blurhash")
+	examples.SetValue(cmd, "max-dimension", "2000")
+	examples.SetValue(cmd, "image-quality", "75")
+	examples.Add(cmd, "", "text", "image", "max-dimension", "image-quality")
+	examples.Add(cmd, "", "text", "image", "compress")
+	examples.SetValue(cmd, "video", "clip.mp4")
+	examples.SetValue(cmd, "video-alt", "A cat chasing a laser pointer")
+	examples.Add(cmd, "", "text", "video", "video-alt")
+	examples.SetValue(cmd, "gif", "https://tenor.com/view/happy-dance-gif-12345678")
+	examples.SetValue(cmd, "gif-alt", "A cat dancing on its hind legs")
+	examples.Add(cmd, "", "text", "gif", "gif-alt")
+	examples.SetValue(cmd, "card", "https://go.dev/blog")
+	examples.Add(cmd, "", "text", "card")
+	examples.Add(cmd, "", "text", "card", "embed-external-no-thumb")
+	examples.SetValue(cmd, "place", "Paris, France")
+	examples.SetValue(cmd, "coords", "48.8566,2.3522")
+	examples.Add(cmd, "", "text", "place", "coords")
+	examples.Add(cmd, "", "text", "reply-to")
+	examples.Add(cmd, "", "text", "reply-allow")
+	examples.Add(cmd, "", "text", "strict")
+	examples.Add(cmd, "", "text", "auto-thread")
+	examples.SetValue(cmd, "max-length", "500")
+	examples.Add(cmd, "", "text", "max-length")
+	examples.SetValue(cmd, "template", "daily")
+	examples.SetValue(cmd, "var", "day=5", "project=yabc", "note=shipped templates")
+	examples.Add(cmd, "", "template", "var")
 
 	return cmd
 }
+
+// postLengthLimit returns the --max-length given, or else Bluesky's limit. No
+// server reports its own, so there is nothing to ask for.
+func postLengthLimit(maxLength int) int {
+	if maxLength > 0 {
+		return maxLength
+	}
+	return richtext.MaxLength
+}
+
+// createOutput writes the create command's outcome, as text or, with --json,
+// as a single JSON object
+type createOutput struct {
+	json   bool
+	stdout io.Writer
+	// prompts is where forms are drawn, kept off stdout along with everything
+	// else with --json or --dry-run
+	prompts io.Writer
+}
+
+// newCreateOutput sets up the output. With --json or --dry-run, everything
+// else that would be printed, like upload progress, warnings and forms, goes
+// to stderr instead, so stdout only ever holds JSON. Logs already go to stderr.
+func newCreateOutput(stdout io.Writer, asJSON, dryRun bool) *createOutput {
+	out := &createOutput{json: asJSON, stdout: stdout, prompts: os.Stdout}
+	if asJSON || dryRun {
+		ui.SetJSON(true)
+		out.prompts = os.Stderr
+	}
+	return out
+}
+
+// exitError is an error already reported to the user, which only sets the
+// status yabc exits with
+type exitError struct {
+	code    int
+	message string
+	// err is what caused it, when callers may look for it, like prompt.ErrCancelled
+	err error
+}
+
+func (e *exitError) Error() string {
+	return e.message
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode is the status yabc exits with
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// createdPost is a created post as written by --json
+type createdPost struct {
+	URI       string `json:"uri"`
+	CID       string `json:"cid"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// success writes the created post, or the first post of a thread along with every part
+func (o *createOutput) success(posts []*bluesky.PostCreateResponse) {
+	var result struct {
+		createdPost
+		Thread []createdPost `json:"thread,omitempty"`
+	}
+	for _, post := range posts {
+		created := createdPost{URI: post.URI, CID: post.CID, Text: post.Record.Text, CreatedAt: post.Record.CreatedAt}
+		if result.URI == "" {
+			result.createdPost = created
+		}
+		if len(posts) > 1 {
+			result.Thread = append(result.Thread, created)
+		}
+	}
+	o.write(result)
+}
+
+// fail reports an error, written as {"error": "..."} with --json, and returns
+// the error for the command to exit with status 1
+func (o *createOutput) fail(message string) error {
+	return o.exit(1, message)
+}
+
+// failUsage reports an error in how the command was used, to exit with status 2
+func (o *createOutput) failUsage(message string) error {
+	return o.exit(2, message)
+}
+
+// formFailed reports a form that didn't complete. Cancelling it with Esc or
+// Ctrl-C posts nothing, so yabc exits with status 1 too, but says so on
+// stderr rather than as an error on stdout.
+func (o *createOutput) formFailed(err error) error {
+	err = prompt.Err(err)
+	if !errors.Is(err, prompt.ErrCancelled) {
+		slog.Error("Failed to get user input", "error", err)
+		return o.fail("failed to get user input")
+	}
+	if o.json {
+		o.write(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintln(os.Stderr, "Cancelled.")
+	}
+	return &exitError{code: 1, message: err.Error(), err: err}
+}
+
+func (o *createOutput) exit(code int, message string) error {
+	if o.json {
+		o.write(map[string]string{"error": message})
+	} else {
+		fmt.Fprintln(o.stdout, "Error:", message)
+	}
+	return &exitError{code: code, message: message}
+}
+
+func (o *createOutput) write(v interface{}) {
+	if err := json.NewEncoder(o.stdout).Encode(v); err != nil {
+		slog.Error("Failed to write JSON", "error", err)
+	}
+}
+
+// dryRun writes the createRecord request bodies a post or thread would send
+func (o *createOutput) dryRun(parts []richtext.Text, opts bluesky.PostOptions) error {
+	records, err := bluesky.DryRunThread(parts, opts)
+	if err != nil {
+		return o.fail(err.Error())
+	}
+
+	repo, _ := bluesky.Credentials()
+	if repo == "" {
+		repo = bluesky.DryRunPlaceholder
+	}
+	for _, record := range records {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"repo":       repo,
+			"collection": "app.bsky.feed.post",
+			"record":     record,
+		}, "", "  ")
+		if err != nil {
+			return o.fail(err.Error())
+		}
+		fmt.Fprintln(o.stdout, string(data))
+	}
+
+	if len(replyAllow) > 0 {
+		ui.Info("Would also restrict replies to: %s", strings.Join(replyAllow, ","))
+	}
+	return nil
+}
+
+// confirmMissingAlt asks whether to post images that have no alt text. Without
+// a terminal to ask on, or with --json, the warning alone has to do, so that
+// scripts keep working.
+func confirmMissingAlt(missing int) bool {
+	ui.Warn("%d image(s) have no alt text, which people using screen readers rely on", missing)
+	if createJSON || dryRun {
+		return true
+	}
+
+	confirmed := false
+	err := huh.NewConfirm().
+		Title("Post without alt text?").
+		Description("Describe images with --alt, or derive it from file names with --alt-from-filename.").
+		Value(&confirmed).
+		Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return false
+	}
+	if err != nil {
+		slog.Debug("Could not ask to confirm missing alt text", "error", err)
+		return true
+	}
+	return confirmed
+}
+
+// warnUnresolvedMentions warns about mentioned handles that don't resolve,
+// which are posted as plain text rather than as mentions
+func warnUnresolvedMentions(handles []string) {
+	for _, handle := range handles {
+		ui.Warn("@%s doesn't resolve to an account, it will be posted as plain text", handle)
+	}
+}
+
+// queuePost adds a post to the local queue for "yabc posts flush". Media are
+// kept as absolute paths, since flush may run from another directory.
+func queuePost(out *createOutput, post state.ScheduledPost, images []bluesky.ImageAttachment) error {
+	for _, image := range images {
+		post.Images = append(post.Images, state.ScheduledImage{Path: image.Path, Alt: image.Alt})
+	}
+	for i := range post.Images {
+		if bluesky.IsImageURL(post.Images[i].Path) {
+			continue
+		}
+		path, err := filepath.Abs(post.Images[i].Path)
+		if err != nil {
+			return out.fail(err.Error())
+		}
+		post.Images[i].Path = path
+	}
+	if post.Video != "" {
+		path, err := filepath.Abs(post.Video)
+		if err != nil {
+			return out.fail(err.Error())
+		}
+		post.Video = path
+	}
+
+	// Files only have to exist again when the post goes out, but a typo is best caught now
+	for _, file := range post.Files() {
+		if _, err := os.Stat(file); err != nil {
+			return out.fail("cannot access " + file)
+		}
+	}
+
+	post, err := state.SchedulePost(post)
+	if err != nil {
+		slog.Error("Failed to schedule post", "error", err)
+		return out.fail("Failed to schedule post")
+	}
+
+	ui.Success("Post %s scheduled for %s", post.ID, post.At.Local().Format(time.RFC1123))
+	ui.Info(`Run "yabc posts flush" after then to post it`)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var deleteYes bool
+
+func newDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <uri-or-link>",
+		Short: "Delete one of your posts",
+		Long: `Delete one of your posts, given by at:// URI or bsky.app link.
+
+Replies to the post stay, but show as replies to a deleted post.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			// Don't ask about a post that can't be deleted anyway
+			if _, err := bluesky.ParseOwnPostURI(token, uri); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			if !deleteYes && !prompt.Confirm("Delete this post?", uri) {
+				fmt.Println("Cancelled.")
+				return
+			}
+
+			err = bluesky.DeletePost(token, uri)
+			if errors.Is(err, bluesky.ErrNotAPost) || errors.Is(err, bluesky.ErrPostNotFound) || errors.Is(err, bluesky.ErrNotOwnPost) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to delete post", "uri", uri, "error", err)
+				fmt.Println("Error: Failed to delete post")
+				return
+			}
+
+			fmt.Println("Post deleted:", uri)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Don't ask before deleting")
+
+	examples.Add(cmd, "https://bsky.app/profile/me.bsky.social/post/3k2a4b5c6d7e9")
+	examples.Add(cmd, "at://did:plc:abc123/app.bsky.feed.post/3k2a4b5c6d7e9", "yes")
+
+	return cmd
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	engagementRecent int
+	engagementJSON   bool
+)
+
+// postEngagement is a post's engagement as written by --json
+type postEngagement struct {
+	URI        string `json:"uri"`
+	Text       string `json:"text"`
+	CreatedAt  string `json:"createdAt"`
+	Likes      int    `json:"likes"`
+	Reposts    int    `json:"reposts"`
+	Replies    int    `json:"replies"`
+	Quotes     int    `json:"quotes"`
+	Engagement int    `json:"engagement"`
+}
+
+func newEngagementCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "engagement",
+		Short: "Show the likes, reposts, replies and quotes on your recent posts",
+		Long: `Show the likes, reposts, replies and quotes on your recent posts, most
+engaging first. Use --json to export the numbers, e.g. to a spreadsheet.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if engagementRecent < 1 {
+				fmt.Println("Error: --recent must be at least 1")
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			posts, err := bluesky.RecentPosts(token, engagementRecent)
+			if err != nil {
+				slog.Error("Failed to fetch recent posts", "error", err)
+				fmt.Println("Error: Failed to fetch your recent posts")
+				return
+			}
+
+			sort.SliceStable(posts, func(i, j int) bool {
+				return posts[i].Engagement() > posts[j].Engagement()
+			})
+
+			if engagementJSON {
+				results := []postEngagement{}
+				for _, post := range posts {
+					results = append(results, postEngagement{
+						URI:        post.URI,
+						Text:       post.Record.Text,
+						CreatedAt:  post.Record.CreatedAt,
+						Likes:      post.LikeCount,
+						Reposts:    post.RepostCount,
+						Replies:    post.ReplyCount,
+						Quotes:     post.QuoteCount,
+						Engagement: post.Engagement(),
+					})
+				}
+
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				encoder.SetEscapeHTML(false)
+				if err := encoder.Encode(results); err != nil {
+					slog.Error("Failed to write JSON", "error", err)
+				}
+				return
+			}
+
+			if len(posts) == 0 {
+				fmt.Println("No posts yet")
+				return
+			}
+
+			var rows [][]string
+			for _, post := range posts {
+				rows = append(rows, []string{
+					strconv.Itoa(post.LikeCount),
+					strconv.Itoa(post.RepostCount),
+					strconv.Itoa(post.ReplyCount),
+					strconv.Itoa(post.QuoteCount),
+					render.Time(post.Record.CreatedAt),
+					render.Truncate(post.Record.Text, 50),
+				})
+			}
+			render.Table([]string{"LIKES", "REPOSTS", "REPLIES", "QUOTES", "POSTED", "TEXT"}, rows)
+		},
+	}
+
+	cmd.Flags().IntVarP(&engagementRecent, "recent", "n", 50, "Number of recent posts to include")
+	cmd.Flags().BoolVar(&engagementJSON, "json", false, "Print the results as JSON")
+	render.AddTimeFlags(cmd)
+
+	examples.SetValue(cmd, "recent", "50")
+	examples.Add(cmd, "", "recent")
+	examples.Add(cmd, "", "recent", "json")
+
+	return cmd
+}
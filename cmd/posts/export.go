@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutput string
+)
+
+func newExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all your posts to a JSONL file",
+		Long: `Export every post in your repo to a JSONL file, one post per line,
+with its text, creation date, facets, languages and embed.
+
+The output can be read back with "yabc posts import". Images and other media
+are stored as blobs outside the post, so their CIDs are listed in a "blobs"
+field and would need to be re-uploaded on import.
+
+The output is only replaced once every post has been exported, so a failed
+export leaves an earlier one untouched.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			// Write next to the output so a failed export never replaces a good one
+			file, err := tempfiles.CreateIn(filepath.Dir(exportOutput), filepath.Base(exportOutput)+".*.partial")
+			if err != nil {
+				slog.Error("Failed to create output file", "error", err)
+				fmt.Println("Error: Failed to create output file")
+				return
+			}
+			defer tempfiles.Remove(file.Name())
+			defer file.Close()
+
+			writer := bufio.NewWriter(file)
+			encoder := json.NewEncoder(writer)
+			encoder.SetEscapeHTML(false)
+
+			count := 0
+			err = bluesky.ExportPosts(token, func(post bluesky.ExportedPost) error {
+				count++
+				if count%100 == 0 {
+					slog.Info("Exporting posts", "count", count)
+				}
+				return encoder.Encode(post)
+			})
+			if err == nil {
+				err = writer.Flush()
+			}
+			if err == nil {
+				err = file.Close()
+			}
+			if err != nil {
+				slog.Error("Failed to export posts", "exported", count, "error", err)
+				fmt.Println("Error: Failed to export posts")
+				return
+			}
+			if err := os.Rename(file.Name(), exportOutput); err != nil {
+				slog.Error("Failed to move export into place", "error", err)
+				fmt.Println("Error: Failed to write", exportOutput)
+				return
+			}
+
+			fmt.Printf("Exported %d posts to %s\n", count, exportOutput)
+		},
+	}
+
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "posts.jsonl", "Path of the JSONL file to write")
+
+	examples.SetValue(cmd, "output", "my-posts.jsonl")
+	examples.Add(cmd, "", "output")
+
+	return cmd
+}
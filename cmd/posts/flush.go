@@ -0,0 +1,233 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/richtext"
+	"github.com/alexisbcz/yabc/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var dropMissing bool
+
+func newFlushCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Post the scheduled posts whose time has passed",
+		Long: `Post the posts queued with "yabc posts create --at" whose time has passed,
+oldest first. Run it from cron or a systemd timer to post on time. Each post
+goes out from the account profile it was scheduled with.
+
+A post whose image or video file no longer exists is left in the queue, so
+the file can be put back, unless --drop-missing is given. A post that fails
+for another reason stays queued and is tried again on the next flush.
+
+Flush exits with status 1 if any post due wasn't posted, so cron can report it.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheduled, err := state.ScheduledPosts()
+			if err != nil {
+				slog.Error("Failed to read scheduled posts", "error", err)
+				fmt.Println("Error: Failed to read scheduled posts")
+				return &exitError{code: 1, message: "failed to read scheduled posts"}
+			}
+
+			now := time.Now()
+			var due []state.ScheduledPost
+			for _, post := range scheduled {
+				if !post.At.After(now) {
+					due = append(due, post)
+				}
+			}
+			if len(due) == 0 {
+				fmt.Println("No scheduled posts due")
+				if len(scheduled) > 0 {
+					fmt.Printf("Next one, %s, is due %s\n", scheduled[0].ID, scheduled[0].At.Local().Format(time.RFC1123))
+				}
+				return nil
+			}
+
+			// Log in once per profile that has posts due
+			tokens := map[string]*bluesky.DIDResponse{}
+			failed := 0
+			for _, post := range due {
+				name := post.Profile
+				if name == "" {
+					name = profile.Default
+				}
+				if err := profile.Use(name); err != nil {
+					fmt.Printf("Error: scheduled post %s: %v\n", post.ID, err)
+					failed++
+					continue
+				}
+
+				token, ok := tokens[name]
+				if !ok {
+					if token, err = bluesky.GetToken(); err != nil {
+						slog.Error("Failed to get authentication token", "profile", name, "error", err)
+						fmt.Printf("Error: Failed to authenticate with Bluesky for profile %s\n", name)
+					}
+					// A failed login is remembered too, so it isn't retried for every post
+					tokens[name] = token
+				}
+				if token == nil || !flushPost(token, post) {
+					failed++
+				}
+			}
+
+			// Every failure was reported above, this only sets the exit status
+			if failed > 0 {
+				return &exitError{code: 1, message: fmt.Sprintf("%d of %d scheduled posts due were not posted", failed, len(due))}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dropMissing, "drop-missing", false, "Remove scheduled posts whose image or video file no longer exists")
+
+	examples.Add(cmd, "")
+	examples.Add(cmd, "", "drop-missing")
+
+	return cmd
+}
+
+// flushPost posts one scheduled post, taking it off the queue first so that a
+// flush running at the same time doesn't post it twice. It returns false if
+// the post wasn't posted, unless --drop-missing removed it on purpose.
+func flushPost(token *bluesky.DIDResponse, post state.ScheduledPost) bool {
+	// Media are only read now, and may have been moved or deleted since
+	for _, file := range post.Files() {
+		if _, err := os.Stat(file); err == nil {
+			continue
+		}
+		fmt.Printf("Error: scheduled post %s: %s no longer exists\n", post.ID, file)
+		if !dropMissing {
+			fmt.Println("  Left in the queue: put the file back, or run flush with --drop-missing to remove the post")
+			return false
+		}
+		if _, err := state.RemoveScheduledPost(post.ID); err != nil {
+			slog.Error("Failed to remove scheduled post", "id", post.ID, "error", err)
+			fmt.Println("Error: Failed to remove scheduled post", post.ID)
+			return false
+		}
+		fmt.Println("  Removed from the queue without posting")
+		return true
+	}
+
+	removed, err := state.RemoveScheduledPost(post.ID)
+	if err != nil {
+		slog.Error("Failed to remove scheduled post", "id", post.ID, "error", err)
+		fmt.Println("Error: Failed to remove scheduled post", post.ID)
+		return false
+	}
+	// Another flush took it first, and answers for it
+	if !removed {
+		return true
+	}
+
+	posts, err := postScheduled(token, post)
+	if err != nil {
+		slog.Error("Failed to post scheduled post", "id", post.ID, "error", err)
+		fmt.Printf("Error: scheduled post %s failed: %v\n", post.ID, err)
+
+		// Trying again would repeat the parts of a thread already posted
+		if len(posts) > 0 {
+			fmt.Printf("  %d of its posts were posted, starting at %s, so it was not queued again\n", len(posts), posts[0].URI)
+			return false
+		}
+		if _, err := state.SchedulePost(post); err != nil {
+			slog.Error("Failed to queue post again", "id", post.ID, "error", err)
+			fmt.Println("Error: Failed to queue post again, its text was:", post.Text)
+			return false
+		}
+		fmt.Println("  Left in the queue to try again")
+		return false
+	}
+
+	fmt.Printf("Posted scheduled post %s: %s\n", post.ID, posts[0].URI)
+	return true
+}
+
+// postScheduled turns a queued post back into post options and posts it, the
+// way "yabc posts create" would have
+func postScheduled(token *bluesky.DIDResponse, post state.ScheduledPost) ([]*bluesky.PostCreateResponse, error) {
+	// Split against the limit the post was checked with when it was queued
+	limit := richtext.MaxLength
+	if post.MaxLength > 0 {
+		limit = post.MaxLength
+	}
+
+	// Posts queued by older versions always kept the default quality, even with nothing to re-encode
+	quality := post.Quality
+	if post.MaxDimension == 0 && !post.Compress {
+		quality = 0
+	}
+	opts := bluesky.PostOptions{
+		DedupeImages: post.DedupeImages,
+		ImageOptions: bluesky.ImageOptions{MaxDimension: post.MaxDimension, Quality: quality, Compress: post.Compress, StripMetadata: !post.KeepMetadata},
+		MaxGraphemes: limit,
+		Langs:        post.Langs,
+		Labels:       post.Labels,
+		NoLinkify:    post.NoLinkify,
+		Card:         post.Card,
+		CardNoThumb:  post.NoThumb,
+	}
+	for _, image := range post.Images {
+		opts.Images = append(opts.Images, bluesky.ImageAttachment{Path: image.Path, Alt: image.Alt})
+	}
+	if post.Video != "" {
+		opts.Video = &bluesky.VideoAttachment{Path: post.Video, Alt: post.VideoAlt}
+	}
+	if post.GIF != "" {
+		opts.GIF = &bluesky.GIFAttachment{URL: post.GIF, Alt: post.GIFAlt}
+	}
+	if post.Place != "" {
+		place, err := bluesky.ParsePlace(post.Place, post.Coords)
+		if err != nil {
+			return nil, err
+		}
+		opts.Place = place
+	}
+
+	// The post being replied to may have been deleted while this one waited
+	if post.ReplyTo != "" {
+		parentURI, err := bluesky.ResolveURI(post.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Reply, err = bluesky.ResolveReplyRef(parentURI); err != nil {
+			return nil, err
+		}
+	}
+
+	parts := []richtext.Text{{Text: post.Text}}
+	if post.AutoThread && bluesky.PostLength(post.Text) > limit {
+		parts = richtext.Split(richtext.WithLinks(richtext.Text{Text: post.Text}), limit)
+		if post.NoLinkify {
+			for i := range parts {
+				parts[i].Links = nil
+			}
+		}
+	}
+
+	posts, err := bluesky.CreateThread(token, parts, opts)
+	if err != nil {
+		return posts, err
+	}
+
+	if len(post.ReplyAllow) > 0 {
+		if err := bluesky.CreateThreadgate(token, posts[0].URI, post.ReplyAllow); err != nil {
+			slog.Error("Failed to restrict replies", "uri", posts[0].URI, "error", err)
+			fmt.Println("Warning: Post created, but replies could not be restricted")
+		}
+	}
+	return posts, nil
+}
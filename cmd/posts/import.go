@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFile string
+)
+
+func newImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import posts from a JSONL file",
+		Long: `Import posts from a JSONL file, one post per line.
+
+Each line is a JSON object with a "text" field and optional "createdAt",
+"facets" and "langs" fields. Facets whose byte range doesn't match the text
+are dropped with a warning instead of failing the whole post.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			file, err := os.Open(importFile)
+			if err != nil {
+				slog.Error("Failed to open import file", "error", err)
+				fmt.Println("Error: Failed to open import file")
+				return
+			}
+			defer file.Close()
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			imported, failed := 0, 0
+			scanner := bufio.NewScanner(file)
+			scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+			for lineNumber := 1; scanner.Scan(); lineNumber++ {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+
+				var post bluesky.ImportedPost
+				if err := json.Unmarshal([]byte(line), &post); err != nil {
+					slog.Error("Failed to parse line", "line", lineNumber, "error", err)
+					failed++
+					continue
+				}
+
+				postResp, err := bluesky.ImportPost(token, post)
+				if err != nil {
+					slog.Error("Failed to import post", "line", lineNumber, "error", err)
+					failed++
+					continue
+				}
+
+				slog.Info("Post imported", "line", lineNumber, "uri", postResp.URI)
+				imported++
+			}
+			if err := scanner.Err(); err != nil {
+				slog.Error("Failed to read import file", "error", err)
+			}
+
+			fmt.Printf("Imported %d posts (%d failed)\n", imported, failed)
+		},
+	}
+
+	cmd.Flags().StringVarP(&importFile, "file", "f", "", "Path to the JSONL file to import")
+	cmd.MarkFlagRequired("file")
+
+	examples.SetValue(cmd, "file", "posts.jsonl")
+	examples.Add(cmd, "", "file")
+
+	return cmd
+}
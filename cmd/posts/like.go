@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+func newLikeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "like <uri-or-link>",
+		Short: "Like a post",
+		Long: `Like a post, given by at:// URI or bsky.app link. The URI of the like is
+printed, which is what deleting it takes to unlike the post.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			like, err := bluesky.LikePost(token, uri)
+			if errors.Is(err, bluesky.ErrAlreadyLiked) {
+				fmt.Println("Already liked:", like.URI)
+				return
+			}
+			if errors.Is(err, bluesky.ErrNotAPost) || errors.Is(err, bluesky.ErrPostNotFound) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to like post", "uri", uri, "error", err)
+				fmt.Println("Error: Failed to like post")
+				return
+			}
+
+			fmt.Println("Liked:", like.URI)
+		},
+	}
+
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+
+	return cmd
+}
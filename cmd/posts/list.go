@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listActor  string
+	listLimit  int
+	listCursor string
+	listAll    bool
+	listYes    bool
+
+	likesLimit int
+	likesAll   bool
+	likesYes   bool
+)
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your recent posts, or someone else's",
+		Long: `List recent posts and reposts with their URIs, newest first, e.g. to grab a
+URI to reply to.
+
+Only the first page is shown unless --all is given, which fetches every post
+(up to 10000), asking first when there are very many. The cursor printed after
+a page, passed to --cursor, shows the next one.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if listAll && listCursor != "" {
+				fmt.Println("Error: --cursor and --all can't be used together")
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			actor := listActor
+			if actor == "" {
+				actor = token.DID
+			}
+
+			fetch := func(cursor string, limit int) ([]bluesky.FeedViewPost, string, error) {
+				page, err := bluesky.GetAuthorFeed(token, actor, cursor, limit)
+				if err != nil {
+					return nil, "", err
+				}
+				return page.Feed, page.Cursor, nil
+			}
+
+			var items []bluesky.FeedViewPost
+			var next string
+			if !listAll {
+				items, next, err = fetch(listCursor, listLimit)
+			} else {
+				var profile *bluesky.ProfileViewDetailed
+				profile, err = bluesky.GetProfile(token, actor)
+				if err != nil {
+					slog.Error("Failed to get profile", "actor", actor, "error", err)
+					fmt.Println("Error: Failed to get profile of", actor)
+					return
+				}
+				if !listYes && !prompt.ConfirmLargeFetch(profile.PostsCount, "posts") {
+					return
+				}
+
+				var capped bool
+				items, capped, err = bluesky.FetchAll(fetch)
+				if capped {
+					fmt.Printf("Warning: stopped after %d posts\n", len(items))
+				}
+			}
+			if err != nil {
+				slog.Error("Failed to list posts", "actor", actor, "fetched", len(items), "error", err)
+				fmt.Println("Error: Failed to list posts")
+				return
+			}
+
+			for _, item := range items {
+				render.FeedItem(item)
+				fmt.Println()
+			}
+			if next != "" {
+				command := "yabc posts list"
+				if listActor != "" {
+					command += " --actor " + listActor
+				}
+				fmt.Printf("Next page: %s --cursor %s\n", command, next)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&listActor, "actor", "a", "", "Handle or DID whose posts to list (defaults to yours)")
+	cmd.Flags().IntVarP(&listLimit, "limit", "l", 30, "Number of posts to show without --all")
+	cmd.Flags().StringVar(&listCursor, "cursor", "", "Cursor of the page to show, as printed after the previous page")
+	cmd.Flags().BoolVar(&listAll, "all", false, "Fetch every post, following every page")
+	cmd.Flags().BoolVarP(&listYes, "yes", "y", false, "Don't ask before fetching very many posts")
+	render.AddTimeFlags(cmd)
+
+	examples.SetValue(cmd, "actor", "alice.bsky.social")
+	examples.Add(cmd, "")
+	examples.Add(cmd, "", "actor")
+	examples.Add(cmd, "", "all")
+
+	return cmd
+}
+
+func newLikesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "likes <uri-or-link>",
+		Short: "List who liked a post",
+		Long: `List who liked a post, most recent first.
+
+Only the first page is shown unless --all is given, which fetches every like
+(up to 10000), asking first when there are very many.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			fetch := func(cursor string, limit int) ([]bluesky.Like, string, error) {
+				page, err := bluesky.GetLikes(token, uri, cursor, limit)
+				if err != nil {
+					return nil, "", err
+				}
+				return page.Likes, page.Cursor, nil
+			}
+
+			var likes []bluesky.Like
+			if !likesAll {
+				likes, _, err = fetch("", likesLimit)
+			} else {
+				var posts []bluesky.PostView
+				posts, err = bluesky.GetPosts(token, []string{uri})
+				if err != nil || len(posts) == 0 {
+					slog.Error("Failed to get post", "uri", uri, "error", err)
+					fmt.Println("Error: Failed to get post", uri)
+					return
+				}
+				if !likesYes && !prompt.ConfirmLargeFetch(posts[0].LikeCount, "likes") {
+					return
+				}
+
+				var capped bool
+				likes, capped, err = bluesky.FetchAll(fetch)
+				if capped {
+					fmt.Printf("Warning: stopped after %d likes\n", len(likes))
+				}
+			}
+			if err != nil {
+				slog.Error("Failed to list likes", "uri", uri, "fetched", len(likes), "error", err)
+				fmt.Println("Error: Failed to list likes")
+				return
+			}
+
+			for _, like := range likes {
+				fmt.Printf("%s · %s\n", render.Author(like.Actor), render.Time(like.CreatedAt))
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&likesLimit, "limit", "l", 50, "Number of likes to show without --all")
+	cmd.Flags().BoolVar(&likesAll, "all", false, "Fetch every like, following every page")
+	cmd.Flags().BoolVarP(&likesYes, "yes", "y", false, "Don't ask before fetching very many likes")
+	render.AddTimeFlags(cmd)
+
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8", "all")
+
+	return cmd
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveUnder string
+	moveYes   bool
+)
+
+func newMoveThreadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "move-thread <uri-or-link>",
+		Short: "Turn one of your posts into a reply to another post",
+		Long: `Turn one of your posts into a reply to another post, for when something that
+should have been a reply was posted on its own.
+
+Posts can't be edited, so the post is recreated as a reply with the same text
+and attachments, then the original is deleted. The original's likes, reposts,
+quotes and replies are lost. If recreating the post fails, the original is
+left untouched.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			parentURI, err := bluesky.ResolveURI(moveUnder)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			// Don't ask about a post that can't be moved anyway
+			if _, err := bluesky.ParseOwnPostURI(token, uri); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			if !moveYes {
+				// Say what will be lost, if the app view knows the post
+				description := "Its likes, reposts and replies will be lost."
+				if posts, err := bluesky.GetPosts(token, []string{uri}); err == nil && len(posts) == 1 {
+					post := posts[0]
+					description = fmt.Sprintf("Its %d likes, %d reposts, %d quotes and %d replies will be lost.",
+						post.LikeCount, post.RepostCount, post.QuoteCount, post.ReplyCount)
+				}
+				if !prompt.Confirm("Delete and recreate this post as a reply?", description) {
+					fmt.Println("Cancelled.")
+					return
+				}
+			}
+
+			moved, err := bluesky.MovePost(token, uri, parentURI)
+			if errors.Is(err, bluesky.ErrOriginalNotDeleted) {
+				slog.Error("Failed to delete original post", "uri", uri, "error", err)
+				fmt.Println("Warning: Post recreated as", moved.URI, "but the original could not be deleted")
+				fmt.Println("Delete", uri, "manually to finish moving it")
+				return
+			}
+			if errors.Is(err, bluesky.ErrNotAPost) || errors.Is(err, bluesky.ErrPostNotFound) ||
+				errors.Is(err, bluesky.ErrNotOwnPost) || errors.Is(err, bluesky.ErrMoveUnderItself) ||
+				errors.Is(err, bluesky.ErrReplyParentNotFound) || errors.Is(err, bluesky.ErrReplyRootNotFound) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to move post", "uri", uri, "parent", parentURI, "error", err)
+				fmt.Println("Error: Failed to move post, the original was left untouched")
+				return
+			}
+
+			fmt.Println("Post moved:", moved.URI)
+		},
+	}
+
+	cmd.Flags().StringVar(&moveUnder, "under", "", "URI or bsky.app link of the post to reply to")
+	cmd.Flags().BoolVarP(&moveYes, "yes", "y", false, "Don't ask before deleting the original post")
+	cmd.MarkFlagRequired("under")
+
+	examples.SetValue(cmd, "under", "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+	examples.Add(cmd, "https://bsky.app/profile/me.bsky.social/post/3k2a4b5c6d7e9", "under")
+
+	return cmd
+}
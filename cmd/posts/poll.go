@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pollQuestion string
+	pollOptions  []string
+)
+
+func newPollCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "poll",
+		Short: "Post a poll that people answer by liking an option",
+		Long: `Post a question followed by a self-thread with one reply per option.
+
+Bluesky has no native polls, so people vote by liking the option they choose.
+Use "yabc posts poll-results" with the question URI to tally the votes.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			poll, err := bluesky.CreatePoll(token, pollQuestion, pollOptions)
+			if err != nil {
+				slog.Error("Failed to create poll", "error", err)
+				fmt.Println("Error:", err)
+				if poll != nil {
+					fmt.Println("Question already posted:", poll.Question.URI)
+				}
+				return
+			}
+
+			fmt.Println("Poll created successfully!")
+			fmt.Println("Question:", poll.Question.URI)
+		},
+	}
+
+	cmd.Flags().StringVarP(&pollQuestion, "question", "q", "", "The poll question")
+	cmd.Flags().StringArrayVarP(&pollOptions, "option", "o", []string{}, "A poll option (repeat for each option)")
+	cmd.MarkFlagRequired("question")
+
+	examples.SetValue(cmd, "question", "Tabs or spaces?")
+	examples.SetValue(cmd, "option", "Tabs", "Spaces")
+	examples.Add(cmd, "", "question", "option")
+
+	return cmd
+}
+
+func newPollResultsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "poll-results <question-uri-or-link>",
+		Short: "Tally the votes on a poll",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			questionURI, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			options, err := bluesky.GetPollResults(token, questionURI)
+			if err != nil {
+				slog.Error("Failed to get poll results", "error", err)
+				fmt.Println("Error:", err)
+				return
+			}
+
+			total := 0
+			for _, option := range options {
+				total += option.Likes
+			}
+
+			for _, option := range options {
+				percent := 0.0
+				if total > 0 {
+					percent = float64(option.Likes) * 100 / float64(total)
+				}
+				fmt.Printf("%-40s %4d votes (%5.1f%%)\n", option.Text, option.Likes, percent)
+			}
+			fmt.Printf("\n%d votes in total\n", total)
+		},
+	}
+
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+
+	return cmd
+}
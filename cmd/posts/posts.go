@@ -9,6 +9,24 @@ func NewPostsCommand() *cobra.Command {
 		Short: "Manage posts on Bluesky",
 	}
 	cmd.AddCommand(newCreatePostCommand())
+	cmd.AddCommand(newFlushCommand())
+	cmd.AddCommand(newImportCommand())
+	cmd.AddCommand(newExportCommand())
+	cmd.AddCommand(newThreadCommand())
+	cmd.AddCommand(newLikeCommand())
+	cmd.AddCommand(newRepostCommand())
+	cmd.AddCommand(newQuoteCommand())
+	cmd.AddCommand(newViewCommand())
+	cmd.AddCommand(newDeleteCommand())
+	cmd.AddCommand(newMoveThreadCommand())
+	cmd.AddCommand(newPollCommand())
+	cmd.AddCommand(newPollResultsCommand())
+	cmd.AddCommand(newWatchRepliesCommand())
+	cmd.AddCommand(newVideoStatusCommand())
+	cmd.AddCommand(newEngagementCommand())
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newLikesCommand())
+	cmd.AddCommand(newClearLikesCommand())
 
 	return cmd
 }
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quoteText string
+)
+
+func newRepostCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repost <uri-or-link>",
+		Short: "Repost a post as is, without adding text (use quote to add your own)",
+		Long: `Repost a post to your followers as is. A repost adds no text of its own and
+doesn't appear as a new post; to comment on a post, use "yabc posts quote".`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			warnUnresolvedMentions(bluesky.UnresolvedMentions(quoteText))
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			repost, err := bluesky.RepostPost(token, uri)
+			if errors.Is(err, bluesky.ErrAlreadyReposted) {
+				fmt.Println("Already reposted:", repost.URI)
+				return
+			}
+			if errors.Is(err, bluesky.ErrNotAPost) || errors.Is(err, bluesky.ErrPostNotFound) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to repost", "uri", uri, "error", err)
+				fmt.Println("Error: Failed to repost")
+				return
+			}
+
+			fmt.Println("Reposted:", repost.URI)
+		},
+	}
+
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+
+	return cmd
+}
+
+func newQuoteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "quote <uri-or-link>",
+		Aliases: []string{"repost-with-quote"},
+		Short:   "Post your own text with another post embedded (a quote post)",
+		Long: `Post your own text with another post embedded below it. Unlike a repost, a
+quote is a new post of yours that people can like and reply to separately.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Make sure we are quoting an existing post before posting anything
+			quoted, err := bluesky.ResolvePostRef(uri)
+			if errors.Is(err, bluesky.ErrNotAPost) || errors.Is(err, bluesky.ErrPostNotFound) {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to resolve quoted post", "uri", uri, "error", err)
+				fmt.Println("Error: Failed to resolve the post to quote")
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			post, err := bluesky.CreatePost(token, quoteText, bluesky.PostOptions{Quote: &quoted})
+			if err != nil {
+				slog.Error("Failed to create quote post", "uri", uri, "error", err)
+				fmt.Println("Error: Failed to create quote post")
+				return
+			}
+
+			fmt.Println("Quote posted:", post.URI)
+		},
+	}
+
+	cmd.Flags().StringVarP(&quoteText, "text", "t", "", "Your text above the quoted post")
+	cmd.MarkFlagRequired("text")
+
+	examples.SetValue(cmd, "text", "This is a great thread")
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8", "text")
+
+	return cmd
+}
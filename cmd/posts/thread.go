@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/config"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/richtext"
+	"github.com/spf13/cobra"
+)
+
+var (
+	markdownFile     string
+	splitOn          string
+	threadFile       string
+	delimiter        string
+	threadReplyTo    string
+	startAt          int
+	threadMaxLength  int
+	threadReplyAllow []string
+)
+
+func newThreadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "thread",
+		Short: "Post a thread from a markdown or text file",
+		Long: `Post a thread from a markdown file, one post per "##" section, or per
+paragraph with --split-on paragraph.
+
+Sections longer than a post are split at sentence boundaries, falling back to
+word boundaries. Markdown links become clickable links that are never cut in
+half; other markdown is posted as written.
+
+With --file, a plain text file is posted as written, one post per segment
+between lines holding only the delimiter ("---" by default). Segments are not
+split, so each has to fit in a post.
+
+If a post fails partway, the posts already created are listed. Post the rest
+with --reply-to set to the last of them and --start-at set to the part that failed.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if (markdownFile == "") == (threadFile == "") {
+				fmt.Println("Error: exactly one of --markdown or --file is required")
+				return
+			}
+			if splitOn != "heading" && splitOn != "paragraph" {
+				fmt.Printf("Error: --split-on must be heading or paragraph, got %q\n", splitOn)
+				return
+			}
+			if strings.TrimSpace(delimiter) == "" {
+				fmt.Println("Error: --delimiter can't be empty")
+				return
+			}
+			if cmd.Flags().Changed("max-length") && threadMaxLength < 1 {
+				fmt.Printf("Error: invalid --max-length %d: must be at least 1\n", threadMaxLength)
+				return
+			}
+
+			// Validate reply rules up front so we never post without the requested gate
+			if _, err := bluesky.ParseReplyRules(threadReplyAllow); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if len(threadReplyAllow) > 0 && threadReplyTo != "" {
+				fmt.Println("Error:", bluesky.ErrThreadgateOnReply)
+				return
+			}
+
+			path := markdownFile
+			if threadFile != "" {
+				path = threadFile
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				slog.Error("Failed to read thread file", "path", path, "error", err)
+				fmt.Println("Error: Failed to read", path)
+				return
+			}
+
+			limit := postLengthLimit(threadMaxLength)
+			var parts []richtext.Text
+			if threadFile != "" {
+				// Check every segment up front, so a thread never stops halfway on one that is too long
+				tooLong := false
+				for i, segment := range richtext.SplitOnDelimiter(string(data), strings.TrimSpace(delimiter)) {
+					if length := bluesky.PostLength(segment); length > limit {
+						fmt.Printf("Error: segment %d is %d graphemes, maximum is %d\n", i+1, length, limit)
+						tooLong = true
+					}
+					parts = append(parts, richtext.Text{Text: segment})
+				}
+				if tooLong {
+					return
+				}
+			} else {
+				for _, section := range richtext.SplitMarkdown(string(data), splitOn == "paragraph") {
+					parts = append(parts, richtext.Split(richtext.FromMarkdown(section), limit)...)
+				}
+			}
+			if len(parts) == 0 {
+				fmt.Println("Error:", path, "has nothing to post")
+				return
+			}
+
+			// Skip the parts a previous run already posted
+			if startAt < 1 || startAt > len(parts) {
+				fmt.Printf("Error: --start-at must be between 1 and %d\n", len(parts))
+				return
+			}
+			skipped := startAt - 1
+			parts = parts[skipped:]
+
+			opts := bluesky.PostOptions{MaxGraphemes: limit, NoLinkify: !config.Current().LinkifyEnabled()}
+			if threadReplyTo != "" {
+				parentURI, err := bluesky.ResolveURI(threadReplyTo)
+				if err != nil {
+					fmt.Println("Error:", err)
+					return
+				}
+				if opts.Reply, err = bluesky.ResolveReplyRef(parentURI); err != nil {
+					slog.Error("Failed to resolve reply target", "uri", parentURI, "error", err)
+					fmt.Println("Error:", err)
+					return
+				}
+			}
+
+			// Warn about mistyped mentions, which would be posted as plain text
+			for _, part := range parts {
+				warnUnresolvedMentions(bluesky.UnresolvedMentions(part.Text))
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			posts, err := bluesky.CreateThread(token, parts, opts)
+			if err != nil {
+				slog.Error("Failed to create thread", "error", err)
+				// The error numbers parts from --start-at, so report the cause with the part's number in the file
+				cause := err
+				if unwrapped := errors.Unwrap(err); unwrapped != nil {
+					cause = unwrapped
+				}
+				fmt.Printf("Error: part %d of %d failed: %v\n", skipped+len(posts)+1, skipped+len(parts), cause)
+				if len(posts) > 0 {
+					fmt.Printf("%d posts already posted:\n", len(posts))
+					for i, post := range posts {
+						fmt.Printf("  %d. %s\n", skipped+i+1, post.URI)
+					}
+					fmt.Printf("Resume with: --reply-to %s --start-at %d\n", posts[len(posts)-1].URI, skipped+len(posts)+1)
+				}
+				return
+			}
+
+			// Restrict who can reply, which the first post's threadgate does for the whole thread
+			if len(threadReplyAllow) > 0 {
+				if err := bluesky.CreateThreadgate(token, posts[0].URI, threadReplyAllow); err != nil {
+					slog.Error("Failed to restrict replies", "uri", posts[0].URI, "error", err)
+					fmt.Println("Warning: Thread created, but replies could not be restricted")
+				}
+			}
+
+			fmt.Printf("Thread of %d posts created successfully!\n", len(posts))
+			fmt.Println("First post:", posts[0].URI)
+		},
+	}
+
+	cmd.Flags().StringVarP(&markdownFile, "markdown", "m", "", "Markdown file to turn into a thread")
+	cmd.Flags().StringVar(&splitOn, "split-on", "heading", `Start a new post at each "heading" or each "paragraph"`)
+	cmd.Flags().StringVarP(&threadFile, "file", "f", "", "Text file to post as a thread, one post per segment")
+	cmd.Flags().StringVar(&delimiter, "delimiter", "---", "Line that separates the segments of --file")
+	cmd.Flags().StringVarP(&threadReplyTo, "reply-to", "r", "", "URI or bsky.app link of the post to reply to with the thread")
+	cmd.Flags().IntVar(&startAt, "start-at", 1, "Number of the first part to post, to resume a thread that failed partway")
+	cmd.Flags().StringSliceVar(&threadReplyAllow, "reply-allow", []string{}, "Who can reply: any combination of mentioned,following,followers, or nobody")
+	cmd.Flags().IntVar(&threadMaxLength, "max-length", 0, "Most graphemes (characters as displayed, an emoji counting once) allowed in a post (default: 300, Bluesky's limit)")
+
+	examples.SetValue(cmd, "markdown", "post.md")
+	examples.SetValue(cmd, "split-on", "paragraph")
+	examples.Add(cmd, "", "markdown")
+	examples.Add(cmd, "", "markdown", "split-on")
+	examples.SetValue(cmd, "file", "thread.txt")
+	examples.SetValue(cmd, "delimiter", "===")
+	examples.Add(cmd, "", "file")
+	examples.Add(cmd, "", "file", "delimiter")
+	examples.SetValue(cmd, "reply-allow", "following")
+	examples.Add(cmd, "", "file", "reply-allow")
+
+	return cmd
+}
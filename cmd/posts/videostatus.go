@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/state"
+	"github.com/spf13/cobra"
+)
+
+func newVideoStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "video-status [jobid]",
+		Short: "Check the processing state of uploaded videos",
+		Long: `Check the processing state of a video job, or of every video uploaded
+recently when no job ID is given.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 1 {
+				printVideoJobStatus(args[0], "")
+				return
+			}
+
+			// Saved jobs are per account, so we need to know who is logged in
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			jobs, err := state.VideoJobsFor(token.DID)
+			if err != nil {
+				slog.Error("Failed to read saved video jobs", "error", err)
+				fmt.Println("Error: Failed to read saved video jobs")
+				return
+			}
+			if len(jobs) == 0 {
+				fmt.Println("No recent video uploads")
+				return
+			}
+
+			for _, job := range jobs {
+				printVideoJobStatus(job.ID, job.Path)
+			}
+		},
+	}
+
+	examples.Add(cmd, "")
+	examples.Add(cmd, "a1b2c3d4-e5f6-7890-abcd-ef1234567890")
+
+	return cmd
+}
+
+func printVideoJobStatus(jobID, path string) {
+	status, err := bluesky.GetVideoJobStatus(jobID)
+	if err != nil {
+		slog.Error("Failed to get video job status", "jobId", jobID, "error", err)
+		fmt.Printf("%s: could not get status\n", jobID)
+		return
+	}
+
+	line := fmt.Sprintf("%s: %s", jobID, status.State)
+	if path != "" {
+		line = fmt.Sprintf("%s (%s)", line, path)
+	}
+	if !status.Done() {
+		line += fmt.Sprintf(", %d%%", status.Progress)
+	}
+	if status.State == bluesky.JobStateFailed {
+		line += fmt.Sprintf(": %s %s", status.Error, status.Message)
+	}
+	fmt.Println(line)
+}
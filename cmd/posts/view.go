@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// maxThreadDepth is the deepest getPostThread goes
+const maxThreadDepth = 1000
+
+var viewDepth int
+
+func newViewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view <uri-or-link>",
+		Short: "Show a post and its replies",
+		Long: `Show a post and the replies below it, each level of replies indented
+further. Use --depth to limit how many levels of replies are shown, 0 for
+the post alone.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if viewDepth < 0 || viewDepth > maxThreadDepth {
+				fmt.Printf("Error: --depth must be between 0 and %d\n", maxThreadDepth)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			thread, err := bluesky.GetPostThread(token, uri, viewDepth)
+			if err != nil {
+				slog.Error("Failed to get post thread", "uri", uri, "error", err)
+				fmt.Println("Error: Failed to get post", uri)
+				return
+			}
+
+			render.Thread(&thread.Thread, viewDepth)
+		},
+	}
+
+	cmd.Flags().IntVarP(&viewDepth, "depth", "d", 6, "How many levels of replies to show")
+	render.AddTimeFlags(cmd)
+
+	examples.SetValue(cmd, "depth", "1")
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8", "depth")
+
+	return cmd
+}
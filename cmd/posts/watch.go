@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package posts
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// minWatchInterval keeps polling well clear of Bluesky's rate limits
+	minWatchInterval = 10 * time.Second
+	// maxWatchBackoff caps how long we wait between polls after repeated errors
+	maxWatchBackoff = 5 * time.Minute
+)
+
+var (
+	watchInterval time.Duration
+	watchDepth    int
+)
+
+func newWatchRepliesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch-replies <uri-or-link>",
+		Short: "Print new replies to a post as they arrive",
+		Long: `Periodically fetch a post's thread and print any replies that weren't
+there on the previous check. Press Ctrl-C to stop.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			uri, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			interval := watchInterval
+			if interval < minWatchInterval {
+				slog.Warn("Interval too short, using minimum", "interval", interval, "minimum", minWatchInterval)
+				interval = minWatchInterval
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			seen := map[string]bool{}
+			first := true
+			wait := interval
+			for {
+				thread, err := bluesky.GetPostThread(token, uri, watchDepth)
+				if err != nil {
+					// Back off on errors, which are often rate limits
+					wait = min(wait*2, maxWatchBackoff)
+					slog.Warn("Failed to fetch thread, backing off", "error", err, "retryIn", wait)
+				} else {
+					wait = interval
+					replies := collectReplies(&thread.Thread, nil)
+
+					for _, reply := range replies {
+						if seen[reply.URI] {
+							continue
+						}
+						seen[reply.URI] = true
+
+						// Replies already there when we start are only counted
+						if !first {
+							render.Post(reply)
+							fmt.Println()
+						}
+					}
+
+					if first {
+						fmt.Printf("Watching %s (%d replies so far)\n", uri, len(replies))
+						first = false
+					}
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&watchInterval, "interval", 30*time.Second, "How often to check for new replies")
+	cmd.Flags().IntVar(&watchDepth, "depth", 10, "How many levels of nested replies to watch")
+	render.AddTimeFlags(cmd)
+
+	examples.SetValue(cmd, "interval", "1m")
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8", "interval")
+
+	return cmd
+}
+
+// collectReplies flattens every visible reply below node, in thread order
+func collectReplies(node *bluesky.ThreadViewPost, replies []bluesky.PostView) []bluesky.PostView {
+	for i := range node.Replies {
+		reply := &node.Replies[i]
+		if !reply.IsPost() {
+			continue
+		}
+		replies = append(replies, reply.Post)
+		replies = collectReplies(reply, replies)
+	}
+	return replies
+}
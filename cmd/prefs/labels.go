@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package prefs
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+func newLabelsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "labels",
+		Short: "Show how posts with content labels are shown to you",
+		Long: `Show whether posts carrying each content label are hidden, shown with a
+warning, or shown as usual. Labels you never changed use the app's default.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			prefs, err := bluesky.GetPreferences(token)
+			if err != nil {
+				slog.Error("Failed to get preferences", "error", err)
+				fmt.Println("Error: Failed to get your preferences")
+				return
+			}
+
+			var rows [][]string
+			set := map[string]bool{}
+			for _, label := range prefs.ContentLabels() {
+				labeler := label.LabelerDID
+				if labeler == "" {
+					labeler = "Bluesky"
+					set[label.Label] = true
+				}
+				rows = append(rows, []string{label.Label, label.Visibility, labeler})
+			}
+			for label, visibility := range bluesky.DefaultLabelVisibility {
+				if !set[label] {
+					rows = append(rows, []string{label, visibility + " (default)", "Bluesky"})
+				}
+			}
+			sort.Slice(rows, func(i, j int) bool {
+				if rows[i][2] != rows[j][2] {
+					return rows[i][2] == "Bluesky"
+				}
+				return rows[i][0] < rows[j][0]
+			})
+
+			render.Table([]string{"LABEL", "VISIBILITY", "LABELER"}, rows)
+			if !prefs.AdultContentEnabled() {
+				fmt.Println("\nAdult content is disabled, so porn, sexual and nudity are hidden whatever their setting.")
+			}
+		},
+	}
+	cmd.AddCommand(newLabelsSetCommand())
+
+	examples.Add(cmd, "")
+
+	return cmd
+}
+
+func newLabelsSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <label> <ignore|show|warn|hide>",
+		Short: "Change how posts with a content label are shown to you",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			label, visibility := args[0], args[1]
+			if !bluesky.LabelVisibilities[visibility] {
+				fmt.Printf("Error: invalid visibility %q: expected ignore, show, warn or hide\n", visibility)
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			prefs, err := bluesky.GetPreferences(token)
+			if err != nil {
+				slog.Error("Failed to get preferences", "error", err)
+				fmt.Println("Error: Failed to get your preferences")
+				return
+			}
+
+			updated, err := prefs.SetContentLabel(label, visibility)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err := bluesky.PutPreferences(token, updated); err != nil {
+				slog.Error("Failed to save preferences", "error", err)
+				fmt.Println("Error: Failed to save your preferences")
+				return
+			}
+
+			fmt.Printf("Posts labelled %s: %s\n", label, visibility)
+			if bluesky.NeedsAdultContent(label, visibility) && !prefs.AdultContentEnabled() {
+				fmt.Println("Warning: adult content is disabled for your account, so this has no effect until you enable it in the Bluesky app's moderation settings")
+			}
+		},
+	}
+
+	examples.Add(cmd, "porn warn")
+	examples.Add(cmd, "graphic-media hide")
+
+	return cmd
+}
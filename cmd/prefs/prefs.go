@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package prefs
+
+import "github.com/spf13/cobra"
+
+func NewPrefsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prefs",
+		Short: "View and change your account preferences",
+	}
+	cmd.AddCommand(newLabelsCommand())
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package repo
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupOutput string
+)
+
+// progressWriter counts the bytes written through it, printing the total every so often
+type progressWriter struct {
+	written int64
+	printed time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if time.Since(p.printed) > 200*time.Millisecond {
+		fmt.Printf("\rDownloaded %s", render.Bytes(p.written))
+		p.printed = time.Now()
+	}
+	return len(b), nil
+}
+
+func newBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Download your whole repo as a CAR file",
+		Long: `Download your whole repo (posts, likes, follows, profile and every other
+record) as a CAR file, the format other ATProto tools import.
+
+The file is written as it downloads, so large repos don't need to fit in
+memory, and only replaces the output once the download is complete. Images
+and videos are stored separately as blobs and are not included.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			// Download next to the output so an interrupted backup never replaces a good one
+			file, err := tempfiles.CreateIn(filepath.Dir(backupOutput), filepath.Base(backupOutput)+".*.partial")
+			if err != nil {
+				slog.Error("Failed to create output file", "error", err)
+				fmt.Println("Error: Failed to create output file")
+				return
+			}
+			defer tempfiles.Remove(file.Name())
+			defer file.Close()
+
+			progress := &progressWriter{}
+			size, err := bluesky.DownloadRepo(cmd.Context(), token, io.MultiWriter(file, progress))
+			fmt.Println()
+			if err != nil {
+				slog.Error("Failed to download repo", "downloaded", size, "error", err)
+				fmt.Println("Error: Failed to download repo")
+				return
+			}
+
+			if _, err := file.Seek(0, 0); err != nil {
+				slog.Error("Failed to read back backup", "error", err)
+				fmt.Println("Error: Failed to verify backup")
+				return
+			}
+			if err := bluesky.CheckCAR(file); err != nil {
+				slog.Error("Downloaded repo is not a valid CAR file", "size", size, "error", err)
+				fmt.Println("Error: Downloaded repo is not a valid CAR file:", err)
+				return
+			}
+
+			if err := file.Close(); err != nil {
+				slog.Error("Failed to write backup", "error", err)
+				fmt.Println("Error: Failed to write backup")
+				return
+			}
+			if err := os.Rename(file.Name(), backupOutput); err != nil {
+				slog.Error("Failed to move backup into place", "error", err)
+				fmt.Println("Error: Failed to write", backupOutput)
+				return
+			}
+
+			fmt.Printf("Backed up %s to %s (%s)\n", token.Handle, backupOutput, render.Bytes(size))
+		},
+	}
+
+	cmd.Flags().StringVarP(&backupOutput, "output", "o", "repo.car", "File to write the CAR backup to")
+
+	examples.SetValue(cmd, "output", "backup-2025-01-01.car")
+	examples.Add(cmd, "")
+	examples.Add(cmd, "", "output")
+
+	return cmd
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package repo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+var (
+	repoOverride string
+	raw          bool
+)
+
+func newGetRecordCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-record <uri-or-link>",
+		Short: "Fetch a single record from a repo",
+		Long: `Fetch a single record from an ATProto repo by its at:// URI or bsky.app link.
+
+With --raw, the full record is printed exactly as stored, pretty-printed,
+including fields yabc does not natively render.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			resolved, err := bluesky.ResolveURI(args[0])
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			uri, err := bluesky.ParseATURI(resolved)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+
+			// Allow fetching the same collection/rkey from another repo
+			if repoOverride != "" {
+				uri.Repo = repoOverride
+			}
+
+			record, err := bluesky.GetRecord(uri.Repo, uri.Collection, uri.RKey)
+			if err != nil {
+				slog.Error("Failed to get record", "uri", uri.String(), "error", err)
+				fmt.Println("Error: Failed to get record")
+				return
+			}
+
+			if raw {
+				var out bytes.Buffer
+				if err := json.Indent(&out, record.Value, "", "  "); err != nil {
+					slog.Error("Failed to format record", "error", err)
+					fmt.Println("Error: Failed to format record")
+					return
+				}
+				fmt.Println(out.String())
+				return
+			}
+
+			var value map[string]interface{}
+			if err := json.Unmarshal(record.Value, &value); err != nil {
+				slog.Error("Failed to decode record", "error", err)
+				fmt.Println("Error: Failed to decode record")
+				return
+			}
+
+			fmt.Println("URI: ", record.URI)
+			fmt.Println("CID: ", record.CID)
+			fmt.Println("Type:", value["$type"])
+			if createdAt, ok := value["createdAt"].(string); ok {
+				fmt.Println("Date:", createdAt)
+			}
+			if text, ok := value["text"].(string); ok {
+				fmt.Println()
+				fmt.Println(text)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&repoOverride, "repo", "r", "", "Fetch from this repo (handle or DID) instead of the one in the URI")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Print the full record JSON as stored")
+
+	examples.SetValue(cmd, "repo", "bob.bsky.social")
+	examples.Add(cmd, "at://did:plc:abc123/app.bsky.feed.post/3k2a4b5c6d7e8", "raw")
+	examples.Add(cmd, "https://bsky.app/profile/alice.bsky.social/post/3k2a4b5c6d7e8")
+	examples.Add(cmd, "at://alice.bsky.social/app.bsky.actor.profile/self", "repo")
+
+	return cmd
+}
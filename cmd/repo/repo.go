@@ -0,0 +1,15 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package repo
+
+import "github.com/spf13/cobra"
+
+func NewRepoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Inspect and back up ATProto repos",
+	}
+	cmd.AddCommand(newGetRecordCommand())
+	cmd.AddCommand(newBackupCommand())
+
+	return cmd
+}
@@ -2,9 +2,34 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"github.com/alexisbcz/yabc/cmd/account"
+	"github.com/alexisbcz/yabc/cmd/apppasswords"
+	"github.com/alexisbcz/yabc/cmd/auth"
+	"github.com/alexisbcz/yabc/cmd/feed"
+	"github.com/alexisbcz/yabc/cmd/graph"
+	"github.com/alexisbcz/yabc/cmd/handle"
+	"github.com/alexisbcz/yabc/cmd/notifications"
 	"github.com/alexisbcz/yabc/cmd/posts"
+	"github.com/alexisbcz/yabc/cmd/prefs"
+	"github.com/alexisbcz/yabc/cmd/repo"
+	"github.com/alexisbcz/yabc/cmd/search"
+	"github.com/alexisbcz/yabc/cmd/settings"
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/config"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+	"github.com/alexisbcz/yabc/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -14,16 +39,177 @@ var rootCmd = &cobra.Command{
 	Long: `yabc is a simple CLI tool to interact with the Bluesky social network.
 It allows users to perform common actions such as posting, browsing feeds,
 and managing their accounts directly from the command line.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Errors from here on are in the settings rather than in how the command was used
+		cmd.SilenceUsage = true
+
+		// Set the log level before anything gets a chance to log
+		if verbose && quiet {
+			return errors.New("--verbose and --quiet can't be used together")
+		}
+		slog.SetLogLoggerLevel(logLevel())
+		ui.SetQuiet(quiet)
+
+		// Ctrl-C cancels this context, stopping requests in flight
+		bluesky.SetContext(cmd.Context())
+
+		// Flags and environment variables override the config file, so load it first
+		if err := config.Init(); err != nil {
+			return err
+		}
+
+		if profileName == "" {
+			profileName = os.Getenv("YABC_PROFILE")
+		}
+		if profileName != "" {
+			if err := profile.Use(profileName); err != nil {
+				return err
+			}
+		}
+
+		if noCache {
+			bluesky.DisableSessionCache()
+		}
+
+		if !cmd.Flags().Changed("max-retries") && config.Current().MaxRetries != nil {
+			maxRetries = *config.Current().MaxRetries
+		}
+		if err := bluesky.SetMaxRetries(maxRetries); err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("timeout") && config.Current().Timeout != nil {
+			timeout = *config.Current().Timeout
+		}
+		if err := bluesky.SetTimeout(timeout); err != nil {
+			return err
+		}
+		if proxy == "" {
+			proxy = config.Current().Proxy
+		}
+		if proxy != "" {
+			if err := bluesky.SetProxy(proxy); err != nil {
+				return err
+			}
+		}
+
+		if pds == "" {
+			pds = os.Getenv("YABC_PDS")
+		}
+		if pds == "" {
+			pds = config.Current().PDS
+		}
+		if pds != "" {
+			if err := bluesky.OverridePDS(pds); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
 }
 
+var (
+	noCache     bool
+	pds         string
+	profileName string
+	maxRetries  int
+	timeout     time.Duration
+	proxy       string
+	verbose     bool
+	quiet       bool
+)
+
+// logLevel returns the least severe level logged, from --verbose and --quiet
+func logLevel() slog.Level {
+	switch {
+	case verbose:
+		return slog.LevelDebug
+	case quiet:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// interruptGrace is how long a command has to stop after Ctrl-C before yabc exits anyway
+const interruptGrace = 5 * time.Second
+
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// On Ctrl-C or SIGTERM, cancel the requests in flight, so the command stops
+	// and returns, and clean up temporary files once it has. A command that
+	// doesn't stop in time, or a second signal, ends yabc anyway.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	var caught atomic.Int32
+	go func() {
+		sig := <-signals
+		slog.Debug("Received signal, stopping", "signal", sig)
+		caught.Store(int32(sig.(syscall.Signal)))
+		cancel()
+
+		select {
+		case <-signals:
+		case <-time.After(interruptGrace):
+		}
+		tempfiles.Cleanup()
+		os.Exit(128 + int(caught.Load()))
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	tempfiles.Cleanup()
+	if sig := caught.Load(); sig != 0 {
+		os.Exit(128 + int(sig))
+	}
 	if err != nil {
+		// Commands that already reported their error pick the exit status
+		var exit interface{ ExitCode() int }
+		if errors.As(err, &exit) {
+			os.Exit(exit.ExitCode())
+		}
+		// Cobra reports other errors, unless the command silenced it to report its own
+		if cmd.SilenceErrors {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().StringVar(&pds, "pds", "", "Host name or URL of your PDS, found from your handle by default (or set YABC_PDS, or pds in the config file)")
+	examples.SetValue(rootCmd, "pds", "pds.example.com")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Log in afresh instead of reusing the session saved in ~/.config/yabc/session.json")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", `Account profile to use, as named with "yabc auth switch" (or set YABC_PROFILE)`)
+	examples.SetValue(rootCmd, "profile", "work")
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", "", "URL of the proxy to send requests through, e.g. http://proxy.example.com:8080 (default: from HTTPS_PROXY, or proxy in the config file)")
+	examples.SetValue(rootCmd, "proxy", "http://proxy.example.com:8080")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", bluesky.DefaultTimeout, "Longest a request may take, 0 for no limit, with extra time for uploads (or timeout in the config file)")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Log debug details, like requests being retried, to stderr")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Log only errors to stderr, and print only what was asked for, like --json output")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", bluesky.DefaultMaxRetries, "Times to retry a request refused for rate limiting, waiting for the limit to reset (or max_retries in the config file)")
 	rootCmd.AddCommand(posts.NewPostsCommand())
+	rootCmd.AddCommand(feed.NewFeedCommand())
+	rootCmd.AddCommand(search.NewSearchCommand())
+	rootCmd.AddCommand(notifications.NewNotificationsCommand())
+	rootCmd.AddCommand(graph.NewGraphCommand())
+	rootCmd.AddCommand(repo.NewRepoCommand())
+	rootCmd.AddCommand(apppasswords.NewAppPasswordsCommand())
+	rootCmd.AddCommand(account.NewAccountCommand())
+	rootCmd.AddCommand(handle.NewHandleCommand())
+	rootCmd.AddCommand(prefs.NewPrefsCommand())
+	rootCmd.AddCommand(auth.NewAuthCommand())
+	rootCmd.AddCommand(auth.NewLoginCommand())
+	rootCmd.AddCommand(auth.NewLogoutCommand())
+	rootCmd.AddCommand(auth.NewWhoamiCommand())
+	rootCmd.AddCommand(settings.NewConfigCommand())
+	rootCmd.AddCommand(newVersionCommand())
+
+	// --version prints the same as the version command
+	rootCmd.Version, _, _ = buildInfo()
+	cobra.AddTemplateFunc("versionText", versionText)
+	rootCmd.SetVersionTemplate("{{versionText}}")
+
+	// Generate examples once the whole command tree is assembled
+	examples.Generate(rootCmd)
 }
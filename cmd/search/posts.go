@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package search
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	postsLimit  int
+	postsAuthor string
+	postsSince  string
+	postsUntil  string
+	postsCursor string
+)
+
+func newPostsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "posts <query>",
+		Short: "Search posts",
+		Long: `Search posts, showing each with its URI, to like, quote or reply to it.
+
+The query is searched as in the Bluesky app, so it can hold the app's
+operators too, like "from:alice.bsky.social" or "#golang". Narrow the search
+down to an author with --author, and to a time span with --since and --until,
+given as a date like 2025-06-01 or an RFC 3339 time.
+
+Only a page of results is shown: the cursor printed after it, passed to
+--cursor, shows the next one.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			query := strings.TrimSpace(args[0])
+			if query == "" {
+				fmt.Println("Error: the search query can't be empty")
+				return
+			}
+			if postsLimit < 1 || postsLimit > bluesky.MaxSearchLimit {
+				fmt.Printf("Error: --limit must be between 1 and %d\n", bluesky.MaxSearchLimit)
+				return
+			}
+
+			opts := bluesky.SearchOpts{Author: strings.TrimPrefix(postsAuthor, "@"), Limit: postsLimit, Cursor: postsCursor}
+			var err error
+			if opts.Since, err = parseSearchTime("--since", postsSince); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if opts.Until, err = parseSearchTime("--until", postsUntil); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+				fmt.Println("Error: --since must be before --until")
+				return
+			}
+
+			// Get authentication token
+			token, err := bluesky.GetToken()
+			if err != nil {
+				slog.Error("Failed to get authentication token", "error", err)
+				fmt.Println("Error: Failed to authenticate with Bluesky")
+				return
+			}
+
+			results, err := bluesky.SearchPosts(token, query, opts)
+			if err != nil {
+				slog.Error("Failed to search posts", "query", query, "error", err)
+				fmt.Println("Error: Failed to search posts")
+				return
+			}
+
+			if len(results.Posts) == 0 {
+				fmt.Println("No posts found.")
+				return
+			}
+			for _, post := range results.Posts {
+				render.Post(post)
+				fmt.Println()
+			}
+			if results.Cursor != "" {
+				fmt.Printf("Next page: %s --cursor %s\n", nextPageCommand(query), results.Cursor)
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&postsLimit, "limit", "l", 25, "Number of posts to show")
+	cmd.Flags().StringVarP(&postsAuthor, "author", "a", "", "Only show posts by this handle or DID")
+	cmd.Flags().StringVar(&postsSince, "since", "", "Only show posts created from this date or RFC 3339 time")
+	cmd.Flags().StringVar(&postsUntil, "until", "", "Only show posts created before this date or RFC 3339 time")
+	cmd.Flags().StringVar(&postsCursor, "cursor", "", "Cursor of the page to show, as printed after the previous page")
+
+	examples.SetValue(cmd, "author", "alice.bsky.social")
+	examples.SetValue(cmd, "since", "2025-06-01")
+	examples.SetValue(cmd, "until", "2025-07-01")
+	examples.Add(cmd, "golang")
+	examples.Add(cmd, `"release notes"`, "author")
+	examples.Add(cmd, `"#bluesky"`, "since", "until")
+
+	return cmd
+}
+
+// parseSearchTime reads --since or --until, either a date, taken as midnight
+// UTC, or an RFC 3339 time. It returns the zero time when not given.
+func parseSearchTime(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected a date like 2025-06-01 or a time like 2025-06-01T09:00:00Z", flag, value)
+	}
+	return t, nil
+}
+
+// nextPageCommand is the command to run again for the next page, with the same filters
+func nextPageCommand(query string) string {
+	command := fmt.Sprintf("yabc search posts %q", query)
+	if postsAuthor != "" {
+		command += " --author " + postsAuthor
+	}
+	if postsSince != "" {
+		command += " --since " + postsSince
+	}
+	if postsUntil != "" {
+		command += " --until " + postsUntil
+	}
+	if postsLimit != 25 {
+		command += fmt.Sprintf(" --limit %d", postsLimit)
+	}
+	return command
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package search
+
+import (
+	"github.com/alexisbcz/yabc/internal/render"
+	"github.com/spf13/cobra"
+)
+
+func NewSearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Search Bluesky",
+	}
+	cmd.AddCommand(newPostsCommand())
+	render.AddTimeFlags(cmd)
+
+	return cmd
+}
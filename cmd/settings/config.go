@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package settings
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/config"
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show the settings yabc uses",
+		Long: `Show the settings from ~/.config/yabc/config.toml, filled in with the defaults
+for those it doesn't set and with --pds or YABC_PDS when given:
+
+  langs = ["en", "fr"]          # languages of new posts, instead of $LANG
+  pds = "pds.example.com"       # PDS to use, instead of finding it from your handle
+  linkify = false               # leave bare URLs in new posts as plain text
+  warn_missing_alt = false      # post images without alt text without asking
+  strip_metadata = false        # upload images with their EXIF and GPS data
+  expand_emoji = true           # turn :rocket: and other shortcodes into emoji
+  max_retries = 5               # retries of rate-limited requests, 3 by default
+  timeout = "1m"                # longest a request may take, 30s by default
+  proxy = "http://proxy:8080"   # proxy to go through, instead of HTTPS_PROXY
+
+  [templates]
+  daily = "Day {{.day}} of {{.project}}: {{.note}}"
+
+Flags such as --lang, --pds, --linkify, --warn-missing-alt, --strip-metadata,
+--expand-emoji, --max-retries, --timeout and --proxy override these.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := config.Path()
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				fmt.Printf("# %s doesn't exist, showing the defaults\n", path)
+			} else {
+				fmt.Printf("# %s\n", path)
+			}
+
+			// Fill in what the file leaves out with what yabc falls back to
+			resolved := *config.Current()
+			if len(resolved.Langs) == 0 {
+				resolved.Langs = bluesky.DefaultLangs()
+			}
+			if bluesky.PDSOverridden() {
+				resolved.PDS = bluesky.PDS()
+			} else {
+				fmt.Println("# pds is found from your handle when logging in")
+			}
+			linkify, warnMissingAlt, stripMetadata, expandEmoji := resolved.LinkifyEnabled(), resolved.WarnMissingAltEnabled(), resolved.StripMetadataEnabled(), resolved.ExpandEmojiEnabled()
+			resolved.Linkify, resolved.WarnMissingAlt, resolved.StripMetadata, resolved.ExpandEmoji = &linkify, &warnMissingAlt, &stripMetadata, &expandEmoji
+			maxRetries, _ := cmd.Flags().GetInt("max-retries")
+			if !cmd.Flags().Changed("max-retries") && resolved.MaxRetries != nil {
+				maxRetries = *resolved.MaxRetries
+			}
+			resolved.MaxRetries = &maxRetries
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			if !cmd.Flags().Changed("timeout") && resolved.Timeout != nil {
+				timeout = *resolved.Timeout
+			}
+			resolved.Timeout = &timeout
+			if proxy, _ := cmd.Flags().GetString("proxy"); proxy != "" {
+				resolved.Proxy = proxy
+			}
+
+			if err := toml.NewEncoder(os.Stdout).Encode(resolved); err != nil {
+				slog.Error("Failed to encode config", "error", err)
+				fmt.Println("Error: Failed to show config")
+			}
+		},
+	}
+
+	examples.Add(cmd, "")
+
+	return cmd
+}
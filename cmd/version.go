@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+// Version, Commit and Date describe the build, set with e.g.
+//
+//	go build -ldflags "-X github.com/alexisbcz/yabc/cmd.Version=v1.2.0 -X github.com/alexisbcz/yabc/cmd.Commit=$(git rev-parse --short HEAD) -X github.com/alexisbcz/yabc/cmd.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Builds without them, like "go install", fall back to what Go recorded in the binary
+var Version, Commit, Date string
+
+// buildInfo fills in the version, commit and date left unset by ldflags
+func buildInfo() (version, commit, date string) {
+	version, commit, date = Version, Commit, Date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if version == "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && commit == "":
+				commit = setting.Value
+			case setting.Key == "vcs.time" && date == "":
+				date = setting.Value
+			}
+		}
+	}
+
+	if version == "" {
+		version = "dev"
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	if date == "" {
+		date = "unknown"
+	}
+	return version, commit, date
+}
+
+// versionText is what "yabc version" and "yabc --version" print
+func versionText() string {
+	version, commit, date := buildInfo()
+	var b strings.Builder
+	fmt.Fprintf(&b, "yabc %s\n", version)
+	fmt.Fprintf(&b, "commit: %s\n", commit)
+	fmt.Fprintf(&b, "built: %s\n", date)
+	fmt.Fprintf(&b, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return b.String()
+}
+
+func newVersionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show the version of yabc",
+		Long: `Show the version of yabc, the commit and date it was built from, and the Go
+version it was built with. Include this in bug reports.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Print(versionText())
+		},
+	}
+
+	examples.Add(cmd, "")
+
+	return cmd
+}
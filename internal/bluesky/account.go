@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ServerDescription describes a PDS's signup requirements
+type ServerDescription struct {
+	DID                  string   `json:"did"`
+	AvailableUserDomains []string `json:"availableUserDomains"`
+	InviteCodeRequired   bool     `json:"inviteCodeRequired"`
+	PhoneVerification    bool     `json:"phoneVerificationRequired"`
+	Links                struct {
+		PrivacyPolicy  string `json:"privacyPolicy,omitempty"`
+		TermsOfService string `json:"termsOfService,omitempty"`
+	} `json:"links"`
+}
+
+// DescribeServer fetches the PDS's description
+func DescribeServer() (*ServerDescription, error) {
+	var description ServerDescription
+	if err := xrpcGet(nil, "com.atproto.server.describeServer", nil, &description); err != nil {
+		return nil, fmt.Errorf("failed to describe server: %w", err)
+	}
+	return &description, nil
+}
+
+// NewAccount holds the details needed to sign up on a PDS
+type NewAccount struct {
+	Handle     string `json:"handle"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	InviteCode string `json:"inviteCode,omitempty"`
+}
+
+// CreateAccount signs up on the current PDS and returns the new account's session
+func CreateAccount(account NewAccount) (*DIDResponse, error) {
+	switch {
+	case account.Handle == "":
+		return nil, fmt.Errorf("a handle is required")
+	case !strings.Contains(account.Email, "@"):
+		return nil, fmt.Errorf("a valid email address is required")
+	case account.Password == "":
+		return nil, fmt.Errorf("a password is required")
+	}
+
+	// Catch the common problems before attempting the signup
+	description, err := DescribeServer()
+	if err != nil {
+		return nil, err
+	}
+	if description.InviteCodeRequired && account.InviteCode == "" {
+		return nil, fmt.Errorf("%s requires an invite code to sign up", PDS())
+	}
+	if !hasAnySuffix(account.Handle, description.AvailableUserDomains) {
+		return nil, fmt.Errorf("handle %q must end with one of %s", account.Handle, strings.Join(description.AvailableUserDomains, ", "))
+	}
+
+	var session DIDResponse
+	if err := xrpcPost(nil, "com.atproto.server.createAccount", account, &session); err != nil {
+		return nil, describeAccountError(err)
+	}
+	return &session, nil
+}
+
+// describeAccountError turns createAccount's error names into actionable messages
+func describeAccountError(err error) error {
+	var xrpcErr *XRPCError
+	if !errors.As(err, &xrpcErr) {
+		return fmt.Errorf("failed to create account: %w", err)
+	}
+
+	message := strings.ToLower(xrpcErr.Message)
+	switch {
+	case xrpcErr.Name == "InvalidHandle" || xrpcErr.Name == "UnsupportedDomain":
+		return fmt.Errorf("invalid handle: %s", xrpcErr.Message)
+	case xrpcErr.Name == "HandleNotAvailable":
+		return fmt.Errorf("that handle is already taken")
+	case xrpcErr.Name == "InvalidInviteCode" || strings.Contains(message, "invite code"):
+		return fmt.Errorf("invite code problem: %s", xrpcErr.Message)
+	case xrpcErr.Name == "InvalidPassword":
+		return fmt.Errorf("invalid password: %s", xrpcErr.Message)
+	case strings.Contains(message, "email"):
+		return fmt.Errorf("email problem: %s", xrpcErr.Message)
+	}
+	return fmt.Errorf("failed to create account: %w", err)
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
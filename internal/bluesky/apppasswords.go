@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAppPasswordSession is returned when an app password session tries to manage app passwords
+var ErrAppPasswordSession = errors.New("app passwords cannot manage app passwords, log in with your main account password")
+
+// AppPassword is an app password tied to the account
+type AppPassword struct {
+	Name       string `json:"name"`
+	CreatedAt  string `json:"createdAt"`
+	Privileged bool   `json:"privileged,omitempty"`
+}
+
+// ListAppPasswords lists the app passwords tied to the authenticated account
+func ListAppPasswords(token *DIDResponse) ([]AppPassword, error) {
+	if token.IsAppPasswordSession() {
+		return nil, ErrAppPasswordSession
+	}
+
+	var resp struct {
+		Passwords []AppPassword `json:"passwords"`
+	}
+	if err := xrpcGet(token, "com.atproto.server.listAppPasswords", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list app passwords: %w", err)
+	}
+
+	return resp.Passwords, nil
+}
+
+// RevokeAppPassword revokes the app password with the given name
+func RevokeAppPassword(token *DIDResponse, name string) error {
+	if token.IsAppPasswordSession() {
+		return ErrAppPasswordSession
+	}
+
+	if err := xrpcPost(token, "com.atproto.server.revokeAppPassword", map[string]string{"name": name}, nil); err != nil {
+		return fmt.Errorf("failed to revoke app password: %w", err)
+	}
+
+	return nil
+}
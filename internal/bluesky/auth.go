@@ -2,17 +2,63 @@
 package bluesky
 
 import (
-	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
-)
+	"regexp"
+	"strings"
+	"time"
 
-const (
-	API_URL = "https://bsky.social/xrpc"
+	"github.com/alexisbcz/yabc/internal/oauth"
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/ui"
 )
 
+// API_URL is the XRPC endpoint of the PDS yabc talks to, bsky.social unless changed with SetPDS
+var API_URL = "https://bsky.social/xrpc"
+
+// SetPDS points yabc at another PDS, given as a host name or URL
+func SetPDS(pds string) error {
+	if !strings.Contains(pds, "://") {
+		pds = "https://" + pds
+	}
+
+	u, err := url.Parse(pds)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("invalid PDS %q: expected a host name or http(s) URL", pds)
+	}
+
+	API_URL = fmt.Sprintf("%s://%s/xrpc", u.Scheme, u.Host)
+	return nil
+}
+
+// pdsOverridden is set when the user picked the PDS, which then wins over the one found from their DID
+var pdsOverridden bool
+
+// OverridePDS points yabc at the PDS the user chose, e.g. with --pds or YABC_PDS
+func OverridePDS(pds string) error {
+	if err := SetPDS(pds); err != nil {
+		return err
+	}
+	pdsOverridden = true
+	return nil
+}
+
+// PDSOverridden reports whether the user picked the PDS
+func PDSOverridden() bool {
+	return pdsOverridden
+}
+
+// PDS returns the base URL of the PDS yabc talks to
+func PDS() string {
+	return strings.TrimSuffix(API_URL, "/xrpc")
+}
+
 type DIDDoc struct {
 	Context            []string `json:"@context"`
 	ID                 string   `json:"id"`
@@ -40,33 +86,289 @@ type DIDResponse struct {
 	AccessJwt       string `json:"accessJwt"`
 	RefreshJwt      string `json:"refreshJwt"`
 	Active          bool   `json:"active"`
+
+	// oauth is set for sessions from "yabc login --oauth", which use DPoP-bound tokens instead of the JWTs
+	oauth *oauth.Session
+}
+
+// authorize sets the request's credentials and returns the client to send it with
+func (t *DIDResponse) authorize(req *http.Request) *http.Client {
+	if t == nil {
+		return httpClient
+	}
+	if t.oauth != nil {
+		return &http.Client{Transport: t.oauth.Transport(transport)}
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", t.AccessJwt))
+	return httpClient
+}
+
+// IsOAuthSession reports whether the session comes from an OAuth login rather than a password
+func (t *DIDResponse) IsOAuthSession() bool {
+	return t.oauth != nil
+}
+
+// ErrProfileAccount is returned when the credentials a profile logs in with
+// are for another account than the session saved for that profile
+var ErrProfileAccount = errors.New("credentials are for another account than the profile")
+
+// CredentialVars returns the environment variables the active profile's
+// credentials are read from: BLUESKY_IDENTIFIER and BLUESKY_PASSWORD for the
+// default profile, and for another, the same suffixed with its name, e.g.
+// BLUESKY_IDENTIFIER_WORK, so each profile logs in as its own account
+func CredentialVars() (identifierVar, passwordVar string) {
+	identifierVar, passwordVar = "BLUESKY_IDENTIFIER", "BLUESKY_PASSWORD"
+	if name := profile.Active(); name != profile.Default {
+		suffix := "_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		identifierVar, passwordVar = identifierVar+suffix, passwordVar+suffix
+	}
+	return identifierVar, passwordVar
 }
 
+// Credentials returns the identifier and password the active profile logs in with, see CredentialVars
+func Credentials() (identifier, password string) {
+	identifierVar, passwordVar := CredentialVars()
+	return os.Getenv(identifierVar), os.Getenv(passwordVar)
+}
+
+// GetToken logs in with the OAuth session saved by "yabc login --oauth" if
+// there is one, and with the active profile's credentials otherwise
 func GetToken() (*DIDResponse, error) {
-	requestBody, err := json.Marshal(map[string]string{
-		"identifier": os.Getenv("BLUESKY_IDENTIFIER"),
-		"password":   os.Getenv("BLUESKY_PASSWORD"),
-	})
+	session, err := oauth.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, err
+	}
+	if session != nil {
+		return oauthToken(session)
 	}
 
-	url := fmt.Sprintf("%s/com.atproto.server.createSession", API_URL)
+	// Reuse the session from a previous run rather than logging in every time
+	if sessionCache {
+		identifier, _ := Credentials()
+		if token := cachedToken(identifier); token != nil {
+			return token, nil
+		}
+	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	token, err := GetPasswordToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
+	}
+
+	// Never post from, or save over, a profile logged in as another account
+	if err := checkProfileAccount(token); err != nil {
+		return nil, err
+	}
+	if sessionCache {
+		if err := SaveSession(token); err != nil {
+			slog.Warn("Failed to cache session", "error", err)
+		}
+	}
+	return token, nil
+}
+
+// GetPasswordToken logs in with the active profile's credentials, ignoring any OAuth session
+func GetPasswordToken() (*DIDResponse, error) {
+	identifier, password := Credentials()
+	if identifier == "" || password == "" {
+		identifierVar, passwordVar := CredentialVars()
+		return nil, fmt.Errorf("not logged in: run `yabc login`, or set %s and %s", identifierVar, passwordVar)
+	}
+	token, err := Login(identifier, password)
+	if !errors.Is(err, ErrMainPassword) {
+		return token, err
+	}
+
+	// Credentials set for scripts are the user's choice, and there may be no one to ask
+	_, passwordVar := CredentialVars()
+	slog.Warn("Logging in with what looks like a main password", "variable", passwordVar)
+	ui.Warn("%s doesn't look like an app password (xxxx-xxxx-xxxx-xxxx), so gives yabc full control of your account; create one at %s, or set YABC_ALLOW_MAIN_PASSWORD=1", passwordVar, AppPasswordsURL)
+	return LoginMainPassword(identifier, password)
+}
+
+// checkProfileAccount makes sure a fresh login is for the account the active
+// profile's saved session is for, if it has one. Logging a profile in as
+// another account is left to "yabc login"
+func checkProfileAccount(token *DIDResponse) error {
+	saved, err := LoadSession()
+	if err != nil || saved == nil || saved.DID == "" || saved.DID == token.DID {
+		return err
+	}
+	identifierVar, _ := CredentialVars()
+	return fmt.Errorf("%w: profile %s is logged in as @%s (%s), but %s is @%s (%s); run `yabc --profile %s login` to switch it to that account",
+		ErrProfileAccount, profile.Active(), saved.Handle, saved.DID, identifierVar, token.Handle, token.DID, profile.Active())
+}
+
+// Login creates a session with a handle, DID or email and an app password. A
+// password that doesn't look like one is refused with ErrMainPassword, unless
+// YABC_ALLOW_MAIN_PASSWORD=1, so that the user can be asked first.
+func Login(identifier, password string) (*DIDResponse, error) {
+	if password != "" && !ValidateAppPassword(password) && !MainPasswordAllowed() {
+		return nil, ErrMainPassword
+	}
+	return LoginMainPassword(identifier, password)
+}
+
+// LoginMainPassword is Login for a password the user has agreed to log in with,
+// even if it is the account's main password
+func LoginMainPassword(identifier, password string) (*DIDResponse, error) {
+	// Log in on the account's own PDS, which isn't necessarily bsky.social
+	if !pdsOverridden {
+		useAccountPDS(identifier)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	requestBody := map[string]string{
+		"identifier": identifier,
+		"password":   password,
 	}
 
 	var tokenResponse DIDResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := xrpcPost(nil, "com.atproto.server.createSession", requestBody, &tokenResponse); err != nil {
+		return nil, err
 	}
 
+	if err := checkLoginHandle(identifier, &tokenResponse); err != nil {
+		return nil, err
+	}
 	return &tokenResponse, nil
 }
+
+// checkLoginHandle confirms the session is for the account the handle typed
+// at login points to. A handle that changed since is only worth a warning.
+func checkLoginHandle(identifier string, token *DIDResponse) error {
+	identifier = strings.TrimPrefix(identifier, "@")
+	if identifier == "" || strings.Contains(identifier, "@") || strings.HasPrefix(identifier, "did:") || strings.EqualFold(identifier, token.Handle) {
+		return nil
+	}
+
+	did, err := ResolveHandle(identifier)
+	if err != nil {
+		slog.Warn("Could not verify the handle logged in with", "handle", identifier, "error", err)
+		return nil
+	}
+	if did != token.DID {
+		return fmt.Errorf("%s points to %s, but the session is for %s (%s)", identifier, did, token.Handle, token.DID)
+	}
+
+	ui.Warn("logged in as @%s, the current handle of %s", token.Handle, identifier)
+	return nil
+}
+
+// oauthToken turns a saved OAuth session into a token, refreshing it first if it has expired
+func oauthToken(session *oauth.Session) (*DIDResponse, error) {
+	if err := SetPDS(session.PDS); err != nil {
+		return nil, err
+	}
+
+	if session.Expired() {
+		slog.Debug("Refreshing OAuth session", "did", session.DID)
+		if err := session.Refresh(requestContext); err != nil {
+			return nil, fmt.Errorf("%w, run `yabc login --oauth` again", err)
+		}
+		if err := session.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DIDResponse{DID: session.DID, Handle: session.Handle, Active: true, oauth: session}, nil
+}
+
+// RefreshToken exchanges the refresh token for a new session, which also
+// picks up changes to the account such as a new handle
+func RefreshToken(token *DIDResponse) (*DIDResponse, error) {
+	// OAuth tokens don't carry the handle, so ask the PDS for it
+	if token.oauth != nil {
+		refreshed := *token
+		if err := xrpcGet(token, "com.atproto.server.getSession", nil, &refreshed); err != nil {
+			return nil, fmt.Errorf("failed to refresh session: %w", err)
+		}
+		refreshed.oauth.Handle = refreshed.Handle
+		return &refreshed, nil
+	}
+
+	var refreshed DIDResponse
+	refreshToken := &DIDResponse{AccessJwt: token.RefreshJwt}
+	if err := xrpcPost(refreshToken, "com.atproto.server.refreshSession", nil, &refreshed); err != nil {
+		return nil, fmt.Errorf("failed to refresh session: %w", err)
+	}
+	return &refreshed, nil
+}
+
+// GetSession asks the PDS which account a session is for, which also confirms it is still valid
+func GetSession(token *DIDResponse) (*DIDResponse, error) {
+	var session DIDResponse
+	if err := xrpcGet(token, "com.atproto.server.getSession", nil, &session); err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+// AppPasswordsURL is where app passwords are created
+const AppPasswordsURL = "https://bsky.app/settings/app-passwords"
+
+// appPasswordPattern matches the xxxx-xxxx-xxxx-xxxx format of generated app passwords
+var appPasswordPattern = regexp.MustCompile(`^[a-zA-Z0-9]{4}(-[a-zA-Z0-9]{4}){3}$`)
+
+// ValidateAppPassword reports whether a password has the format of an app password.
+// Only a hint: the format isn't guaranteed to stay the same.
+func ValidateAppPassword(password string) bool {
+	return appPasswordPattern.MatchString(strings.TrimSpace(password))
+}
+
+// ErrMainPassword is returned by Login for a password that doesn't look like
+// an app password, so probably the account's main password, which gives full
+// control of the account
+var ErrMainPassword = errors.New("not an app password (xxxx-xxxx-xxxx-xxxx)")
+
+// ErrNotAppPassword is returned when logging in with what looks like a main password is declined
+var ErrNotAppPassword = errors.New("login cancelled: not an app password")
+
+// MainPasswordAllowed reports whether YABC_ALLOW_MAIN_PASSWORD=1 was set, to
+// log in with a main password without being asked
+func MainPasswordAllowed() bool {
+	return os.Getenv("YABC_ALLOW_MAIN_PASSWORD") == "1"
+}
+
+// appPasswordScopes are the JWT scopes of sessions created with an app password
+var appPasswordScopes = map[string]bool{
+	"com.atproto.appPass":           true,
+	"com.atproto.appPassPrivileged": true,
+}
+
+// IsAppPasswordSession reports whether the session was created with an app password
+// rather than the account's main password, based on the access token's scope
+func (t *DIDResponse) IsAppPasswordSession() bool {
+	claims, ok := parseJWT(t.AccessJwt)
+	return ok && appPasswordScopes[claims.Scope]
+}
+
+// jwtClaims are the claims of session JWTs yabc looks at
+type jwtClaims struct {
+	Scope string `json:"scope"`
+	Exp   int64  `json:"exp"`
+}
+
+// parseJWT reads a JWT's claims without verifying it, which is the server's job
+func parseJWT(jwt string) (jwtClaims, bool) {
+	var claims jwtClaims
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return claims, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, false
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, false
+	}
+	return claims, true
+}
+
+// jwtExpired reports whether a JWT has expired or is about to, treating unreadable ones as expired
+func jwtExpired(jwt string) bool {
+	claims, ok := parseJWT(jwt)
+	return !ok || time.Now().Add(time.Minute).Unix() >= claims.Exp
+}
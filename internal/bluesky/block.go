@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+)
+
+// blockCollection holds the block records of a repo, one per blocked account
+const blockCollection = "app.bsky.graph.block"
+
+var (
+	// ErrAlreadyBlocked is returned along with the existing block when blocking an account blocked before
+	ErrAlreadyBlocked = errors.New("already blocked")
+	// ErrNotBlocked is returned when unblocking an account that isn't blocked
+	ErrNotBlocked = errors.New("not blocked")
+	// ErrBlockSelf is returned when blocking or unblocking your own account
+	ErrBlockSelf = errors.New("you can't block yourself")
+)
+
+// Block blocks an account, given by handle or DID, creating an
+// app.bsky.graph.block record. Like follows, blocks are public records in the
+// user's repo. The returned URI is the block's. An account blocked before
+// isn't blocked twice: the existing block is returned with ErrAlreadyBlocked.
+func Block(token *DIDResponse, actor string) (*PostCreateResponse, error) {
+	did, err := resolveActorDID(actor)
+	if err != nil {
+		return nil, err
+	}
+	if did == token.DID {
+		return nil, ErrBlockSelf
+	}
+
+	existing, err := findSubjectRecord(token, blockCollection, did)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &PostCreateResponse{URI: existing.URI, CID: existing.CID}, ErrAlreadyBlocked
+	}
+
+	return CreateRecord(token, blockCollection, map[string]interface{}{
+		"$type":     blockCollection,
+		"subject":   did,
+		"createdAt": getCurrentTime(),
+	})
+}
+
+// Unblock unblocks an account, given by handle or DID, deleting the block
+// record found in the user's repo. It returns the URI of the deleted block,
+// or ErrNotBlocked if there was none.
+func Unblock(token *DIDResponse, actor string) (string, error) {
+	did, err := resolveActorDID(actor)
+	if err != nil {
+		return "", err
+	}
+	if did == token.DID {
+		return "", ErrBlockSelf
+	}
+
+	existing, err := findSubjectRecord(token, blockCollection, did)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		return "", ErrNotBlocked
+	}
+
+	uri, err := ParseATURI(existing.URI)
+	if err != nil {
+		return "", err
+	}
+	if err := DeleteRecord(token, blockCollection, uri.RKey); err != nil {
+		return "", fmt.Errorf("failed to delete block %s: %w", existing.URI, err)
+	}
+	return existing.URI, nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"html"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	exifHeader      = "Exif\x00\x00"
+	xmpHeader       = "http://ns.adobe.com/xap/1.0/\x00"
+	photoshopHeader = "Photoshop 3.0\x00"
+
+	// exifImageDescription is the EXIF tag holding the image's description
+	exifImageDescription = 0x010E
+	// iptcResource is the Photoshop image resource holding IPTC metadata
+	iptcResource = 0x0404
+)
+
+// placeholderCaptions are descriptions some cameras write into every photo,
+// which describe nothing
+var placeholderCaptions = map[string]bool{
+	"OLYMPUS DIGITAL CAMERA":        true,
+	"KONICA MINOLTA DIGITAL CAMERA": true,
+	"DIGITAL CAMERA":                true,
+	"SONY DSC":                      true,
+	"DEFAULT":                       true,
+}
+
+var (
+	// xmpDescriptionElement matches the first entry of an XMP dc:description, as photo editors write it
+	xmpDescriptionElement = regexp.MustCompile(`(?s)<dc:description\b[^>]*>.*?<rdf:li\b[^>]*>(.*?)</rdf:li>`)
+	// xmpDescriptionAttribute matches a dc:description written as an attribute
+	xmpDescriptionAttribute = regexp.MustCompile(`\bdc:description="([^"]*)"`)
+)
+
+// AltFromMetadata returns the caption embedded in an image file, see
+// readImageCaption, or "" for an image URL, which isn't downloaded yet
+func AltFromMetadata(imagePath string) string {
+	if IsImageURL(imagePath) {
+		return ""
+	}
+	return readImageCaption(imagePath)
+}
+
+// readImageCaption reads the caption photographers and photo editors store in
+// a JPEG: the IPTC caption, else the XMP description, else the EXIF image
+// description. It returns "" if there is none or the file isn't a JPEG.
+func readImageCaption(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var iptcCaption, xmpCaption, exifCaption string
+	err = readJPEGSegments(bufio.NewReader(file), func(marker byte, segment []byte) {
+		switch {
+		case marker == 0xE1 && bytes.HasPrefix(segment, []byte(exifHeader)):
+			exifCaption = exifDescription(segment[len(exifHeader):])
+		case marker == 0xE1 && bytes.HasPrefix(segment, []byte(xmpHeader)):
+			xmpCaption = xmpDescription(segment[len(xmpHeader):])
+		case marker == 0xED && bytes.HasPrefix(segment, []byte(photoshopHeader)):
+			iptcCaption = photoshopCaption(segment[len(photoshopHeader):])
+		}
+	})
+	if err != nil {
+		slog.Debug("Could not read image metadata", "path", path, "error", err)
+	}
+
+	for _, caption := range []string{iptcCaption, xmpCaption, exifCaption} {
+		if caption = cleanCaption(caption); caption != "" {
+			return caption
+		}
+	}
+	return ""
+}
+
+// readJPEGSegments calls fn with each metadata segment of a JPEG, stopping at
+// the image data, which the metadata always comes before
+func readJPEGSegments(r *bufio.Reader, fn func(marker byte, segment []byte)) error {
+	var soi [2]byte
+	if _, err := io.ReadFull(r, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return nil
+	}
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b != 0xFF {
+			return nil
+		}
+
+		// Markers may be padded with any number of 0xFF bytes
+		marker := byte(0xFF)
+		for marker == 0xFF {
+			if marker, err = r.ReadByte(); err != nil {
+				return err
+			}
+		}
+		switch {
+		case marker == 0xDA || marker == 0xD9:
+			// Start of scan or end of image: no more metadata
+			return nil
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a length or content
+			continue
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			return err
+		}
+		size := int(binary.BigEndian.Uint16(length[:]))
+		if size < 2 {
+			return nil
+		}
+		segment := make([]byte, size-2)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return err
+		}
+		fn(marker, segment)
+	}
+}
+
+// exifDescription reads the ImageDescription tag from the first IFD of EXIF data
+func exifDescription(tiff []byte) string {
+	order := exifByteOrder(tiff)
+	if order == nil {
+		return ""
+	}
+
+	// Only an ASCII ImageDescription is of use
+	entry, ok := exifEntry(tiff, order, exifImageDescription, 2)
+	if !ok {
+		return ""
+	}
+
+	// Values of up to 4 bytes are stored in the entry itself, longer ones at an offset
+	n := int(order.Uint32(tiff[entry+4:]))
+	start := entry + 8
+	if n > 4 {
+		start = int(order.Uint32(tiff[entry+8:]))
+	}
+	if n < 0 || start < 0 || start+n > len(tiff) {
+		return ""
+	}
+	return decodeCaption(bytes.TrimRight(tiff[start:start+n], "\x00"))
+}
+
+// exifByteOrder returns the byte order EXIF data is written in, or nil if it isn't EXIF
+func exifByteOrder(tiff []byte) binary.ByteOrder {
+	if len(tiff) < 8 {
+		return nil
+	}
+	switch string(tiff[:2]) {
+	case "II":
+		return binary.LittleEndian
+	case "MM":
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// exifEntry finds a tag of the given type in the first IFD of EXIF data,
+// returning the offset of its 12-byte entry
+func exifEntry(tiff []byte, order binary.ByteOrder, tag, typ uint16) (int, bool) {
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0, false
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:]) == tag && order.Uint16(tiff[entry+2:]) == typ {
+			return entry, true
+		}
+	}
+	return 0, false
+}
+
+// xmpDescription reads the dc:description from an XMP packet
+func xmpDescription(xmp []byte) string {
+	for _, pattern := range []*regexp.Regexp{xmpDescriptionElement, xmpDescriptionAttribute} {
+		if match := pattern.FindSubmatch(xmp); match != nil {
+			return html.UnescapeString(string(match[1]))
+		}
+	}
+	return ""
+}
+
+// photoshopCaption finds the IPTC metadata among Photoshop image resources
+// and reads its caption
+func photoshopCaption(resources []byte) string {
+	for len(resources) >= 12 && bytes.HasPrefix(resources, []byte("8BIM")) {
+		id := binary.BigEndian.Uint16(resources[4:6])
+
+		// The resource name is a Pascal string, padded to an even length
+		nameLength := int(resources[6]) + 1
+		nameLength += nameLength % 2
+		if 6+nameLength+4 > len(resources) {
+			return ""
+		}
+		rest := resources[6+nameLength:]
+		size := int(binary.BigEndian.Uint32(rest[:4]))
+		if size < 0 || 4+size > len(rest) {
+			return ""
+		}
+		if id == iptcResource {
+			return iptcCaption(rest[4 : 4+size])
+		}
+
+		// Resource data is padded to an even length too
+		next := 4 + size + size%2
+		if next > len(rest) {
+			return ""
+		}
+		resources = rest[next:]
+	}
+	return ""
+}
+
+// iptcCaption reads the Caption/Abstract dataset (2:120) of IPTC metadata
+func iptcCaption(iptc []byte) string {
+	for len(iptc) >= 5 && iptc[0] == 0x1C {
+		record, dataset := iptc[1], iptc[2]
+		size := int(binary.BigEndian.Uint16(iptc[3:5]))
+		// Extended datasets, over 32767 bytes, are never captions
+		if size&0x8000 != 0 || 5+size > len(iptc) {
+			return ""
+		}
+		if record == 2 && dataset == 120 {
+			return decodeCaption(iptc[5 : 5+size])
+		}
+		iptc = iptc[5+size:]
+	}
+	return ""
+}
+
+// decodeCaption decodes metadata text, which is UTF-8 when written by
+// anything recent and Latin-1 when written by older software
+func decodeCaption(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// cleanCaption trims a caption and drops those that are only camera placeholders
+func cleanCaption(caption string) string {
+	caption = strings.TrimSpace(caption)
+	if placeholderCaptions[strings.ToUpper(caption)] {
+		return ""
+	}
+	return caption
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DeletePacing is the pause between deletions. PDSes allow around 5,000
+// writes an hour, so long cleanups stay just under that.
+const DeletePacing = 750 * time.Millisecond
+
+// ErrRateLimited is returned when the PDS refuses more writes for now
+var ErrRateLimited = errors.New("rate limited")
+
+// ListLikeRecords returns the like records in the user's repo, newest first, up
+// to MaxFetchAll of them, reporting whether there were more
+func ListLikeRecords(token *DIDResponse) ([]RecordResponse, bool, error) {
+	return FetchAll(func(cursor string, limit int) ([]RecordResponse, string, error) {
+		page, err := ListRecords(token, token.DID, "app.bsky.feed.like", cursor, limit)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list likes: %w", err)
+		}
+		return page.Records, page.Cursor, nil
+	})
+}
+
+// DeleteRecords deletes records from the user's repo one at a time, paced for
+// rate limits, calling progress after each. It stops at the first failure and
+// returns how many were deleted, so the rest can be deleted later.
+func DeleteRecords(token *DIDResponse, uris []string, progress func(deleted int)) (int, error) {
+	for i, uri := range uris {
+		if i > 0 {
+			time.Sleep(DeletePacing)
+		}
+
+		parsed, err := ParseATURI(uri)
+		if err != nil {
+			return i, err
+		}
+
+		err = DeleteRecord(token, parsed.Collection, parsed.RKey)
+		var xrpcErr *XRPCError
+		if errors.As(err, &xrpcErr) && xrpcErr.StatusCode == http.StatusTooManyRequests {
+			return i, fmt.Errorf("%w: %w", ErrRateLimited, err)
+		}
+		if err != nil {
+			return i, fmt.Errorf("failed to delete %s: %w", uri, err)
+		}
+		progress(i + 1)
+	}
+	return len(uris), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/oauth"
+)
+
+const (
+	// DefaultTimeout bounds each request unless changed with SetTimeout
+	DefaultTimeout = 30 * time.Second
+	// minUploadRate is the slowest upload speed, in bytes per second, blob uploads are given time for
+	minUploadRate = 50 << 10
+)
+
+// transport is a copy of the default transport, so the proxy can be changed
+// without affecting other packages. Until then it uses the proxy from
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+var transport = http.DefaultTransport.(*http.Transport).Clone()
+
+// httpClient sends every request yabc makes
+var httpClient = &http.Client{Transport: transport}
+
+// proxySchemes are the kinds of proxy the transport can go through
+var proxySchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"socks5": true,
+}
+
+// SetProxy sends every request through the proxy at rawURL, instead of the
+// one from the environment
+func SetProxy(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || !proxySchemes[u.Scheme] {
+		return fmt.Errorf("invalid proxy %q: expected a URL like http://proxy.example.com:8080", rawURL)
+	}
+
+	transport.Proxy = http.ProxyURL(u)
+	oauth.SetTransport(transport)
+	return nil
+}
+
+// requestContext is what every request is made with, so that cancelling it
+// stops the requests in flight
+var requestContext = context.Background()
+
+// SetContext makes requests with ctx, so that cancelling it, as on Ctrl-C,
+// stops uploads and downloads in flight and requests waiting to be retried
+func SetContext(ctx context.Context) {
+	requestContext = ctx
+}
+
+// requestTimeout bounds each request, 0 for no limit
+var requestTimeout = DefaultTimeout
+
+// SetTimeout changes how long a request may take, 0 for no limit
+func SetTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return fmt.Errorf("invalid timeout %s: must be 0 or more", timeout)
+	}
+	requestTimeout = timeout
+	return nil
+}
+
+// withTimeout bounds a request by timeout, leaving alone one that already has a deadline
+func withTimeout(req *http.Request, timeout time.Duration) (*http.Request, context.CancelFunc) {
+	if _, ok := req.Context().Deadline(); ok || timeout <= 0 {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	return req.WithContext(ctx), cancel
+}
+
+// uploadTimeout is the timeout for uploading size bytes, giving slow
+// connections time to send large images and videos
+func uploadTimeout(size int) time.Duration {
+	if requestTimeout <= 0 {
+		return 0
+	}
+	return requestTimeout + time.Duration(size/minUploadRate)*time.Second
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alexisbcz/yabc/internal/oauth"
+)
+
+// restoreProxy puts back the transport's proxy once a test is done
+func restoreProxy(t *testing.T) {
+	previous := transport.Proxy
+	t.Cleanup(func() {
+		transport.Proxy = previous
+		oauth.SetTransport(transport)
+	})
+}
+
+func TestSetProxy(t *testing.T) {
+	restoreProxy(t)
+
+	// The proxy answers for the PDS, which doesn't exist, so only a proxied request can succeed
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "pds.invalid" || r.URL.Path != "/xrpc/com.atproto.server.describeServer" {
+			t.Errorf("proxy got a request for %s", r.URL)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		proxied.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"did":"did:web:pds.invalid","availableUserDomains":[".pds.invalid"]}`)
+	}))
+	t.Cleanup(proxy.Close)
+
+	previousAPI := API_URL
+	API_URL = "http://pds.invalid/xrpc"
+	t.Cleanup(func() { API_URL = previousAPI })
+
+	if err := SetProxy(proxy.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var description ServerDescription
+	if err := xrpcGet(nil, "com.atproto.server.describeServer", nil, &description); err != nil {
+		t.Fatal(err)
+	}
+	if description.DID != "did:web:pds.invalid" {
+		t.Errorf("got DID %q, want did:web:pds.invalid", description.DID)
+	}
+	if n := proxied.Load(); n != 1 {
+		t.Errorf("proxy got %d requests, want 1", n)
+	}
+}
+
+func TestSetProxyInvalid(t *testing.T) {
+	restoreProxy(t)
+
+	for _, rawURL := range []string{
+		"",
+		"proxy.example.com:8080",
+		"ftp://proxy.example.com",
+		"file:///tmp/proxy",
+		"http://",
+		"http://proxy example.com",
+	} {
+		if err := SetProxy(rawURL); err == nil {
+			t.Errorf("SetProxy(%q) succeeded, want an error", rawURL)
+		}
+	}
+}
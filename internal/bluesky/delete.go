@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import "fmt"
+
+// ParseOwnPostURI parses the URI of one of the account's posts, refusing
+// other records with ErrNotAPost and posts in other repos with ErrNotOwnPost,
+// so that they can be caught before asking for confirmation
+func ParseOwnPostURI(token *DIDResponse, uri string) (*ATURI, error) {
+	parsed, err := ParseATURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Collection != "app.bsky.feed.post" {
+		return nil, fmt.Errorf("%w: %s is a %s record", ErrNotAPost, uri, parsed.Collection)
+	}
+	if parsed.Repo != token.DID && parsed.Repo != token.Handle {
+		return nil, fmt.Errorf("%w: %s", ErrNotOwnPost, uri)
+	}
+	return parsed, nil
+}
+
+// DeletePost deletes one of the account's posts. Posts in other repos are
+// refused up front rather than left for the PDS to reject.
+func DeletePost(token *DIDResponse, uri string) error {
+	parsed, err := ParseOwnPostURI(token, uri)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a missing record succeeds, so check first to tell the user about a wrong URI
+	if _, err := GetRecord(parsed.Repo, parsed.Collection, parsed.RKey); isNotFound(err) {
+		return fmt.Errorf("%w: %s", ErrPostNotFound, uri)
+	} else if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+
+	if err := DeleteRecord(token, parsed.Collection, parsed.RKey); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", uri, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexisbcz/yabc/internal/richtext"
+	"github.com/alexisbcz/yabc/internal/ui"
+)
+
+// DryRunPlaceholder stands in for values only known once posted, like CIDs of uploaded blobs
+const DryRunPlaceholder = "(set when posting)"
+
+// DryRunThread builds the records CreateThread would create, without any
+// network call. Media is read and checked locally but not uploaded, and what
+// would be uploaded or fetched is reported through ui, which sends it to
+// stderr in a dry run, keeping stdout for the records.
+func DryRunThread(texts []richtext.Text, opts PostOptions) ([]map[string]interface{}, error) {
+	opts.DryRun = true
+	_, records, err := createThread(nil, texts, opts)
+	return records, err
+}
+
+// DryRunReplyRef stands in for the reply ref of a reply to parent, a URI or
+// bsky.app link, whose CID and thread root are only fetched when posting. A
+// link is only turned into a URI then too, so it isn't passed off as one.
+func DryRunReplyRef(parent string) *ReplyRef {
+	parentURI := fmt.Sprintf("%s for %s", DryRunPlaceholder, parent)
+	if _, err := ParseATURI(parent); err == nil {
+		parentURI = parent
+	}
+	return &ReplyRef{
+		Root:   StrongRef{URI: DryRunPlaceholder, CID: DryRunPlaceholder},
+		Parent: StrongRef{URI: parentURI, CID: DryRunPlaceholder},
+	}
+}
+
+// dryRunResolveHandle resolves handles from the cache only, leaving the rest to resolve when posting
+func dryRunResolveHandle(handle string) (string, error) {
+	resolvedHandles.Lock()
+	did, ok := resolvedHandles.dids[handle]
+	resolvedHandles.Unlock()
+	if ok {
+		return did, nil
+	}
+	return fmt.Sprintf("%s for @%s", DryRunPlaceholder, handle), nil
+}
+
+// dryRunBlob reports an image that would be uploaded and returns a blob with what is known locally
+func dryRunBlob(img *preparedImage) *UploadBlobResponse {
+	if img.file == "" && img.data == nil {
+		ui.Info("Would download and upload image: %s", img.path)
+		img.mimeType = DryRunPlaceholder
+	} else {
+		ui.Info("Would upload image: %s (%s, %d bytes)", img.path, img.mimeType, img.size)
+	}
+
+	blob := &UploadBlobResponse{}
+	blob.Blob.Type = "blob"
+	blob.Blob.Ref.Link = DryRunPlaceholder
+	blob.Blob.MimeType = img.mimeType
+	blob.Blob.Size = img.size
+	return blob
+}
+
+// dryRunVideoEmbed checks a video locally and returns its embed with a placeholder blob
+func dryRunVideoEmbed(video VideoAttachment) (map[string]interface{}, error) {
+	data, err := os.ReadFile(video.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video file: %w", err)
+	}
+	if err := validateVideo(video.Path, data); err != nil {
+		return nil, err
+	}
+	ui.Info("Would upload video: %s (%d bytes) and wait for it to be processed", video.Path, len(data))
+
+	embed := map[string]interface{}{
+		"$type": "app.bsky.embed.video",
+		"video": BlobReference{Type: "blob", Ref: RefLink{Link: DryRunPlaceholder}, MimeType: "video/mp4", Size: int64(len(data))},
+	}
+	if video.Alt != "" {
+		embed["alt"] = video.Alt
+	}
+	return embed, nil
+}
+
+// dryRunGIFEmbed returns a GIF embed whose media URL and size would be looked up on Tenor
+func dryRunGIFEmbed(gif GIFAttachment) map[string]interface{} {
+	ui.Info("Would look up the size of the Tenor GIF %s and upload a still of it", gif.URL)
+	return map[string]interface{}{
+		"$type": "app.bsky.embed.external",
+		"external": map[string]interface{}{
+			"uri":         DryRunPlaceholder,
+			"title":       DryRunPlaceholder,
+			"description": gifDescription(gif.Alt),
+		},
+	}
+}
+
+// dryRunExternalEmbed returns a link card embed whose details would be fetched from the page
+func dryRunExternalEmbed(pageURL string) map[string]interface{} {
+	ui.Info("Would fetch the title, description and image of %s for the link card", pageURL)
+	return map[string]interface{}{
+		"$type": "app.bsky.embed.external",
+		"external": map[string]interface{}{
+			"uri":         pageURL,
+			"title":       DryRunPlaceholder,
+			"description": DryRunPlaceholder,
+		},
+	}
+}
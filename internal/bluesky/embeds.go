@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// AspectRatio is the width to height ratio of an image or video
+type AspectRatio struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// VideoView is a video embed as returned by the app view (app.bsky.embed.video#view)
+type VideoView struct {
+	CID         string       `json:"cid"`
+	Playlist    string       `json:"playlist"`
+	Thumbnail   string       `json:"thumbnail,omitempty"`
+	Alt         string       `json:"alt,omitempty"`
+	AspectRatio *AspectRatio `json:"aspectRatio,omitempty"`
+}
+
+// Processing reports whether the video has been posted but can't be played yet
+func (v *VideoView) Processing() bool {
+	return v.Playlist == ""
+}
+
+// embedType reads the $type of an embed, or "" if there is none
+func embedType(embed json.RawMessage) string {
+	if len(embed) == 0 {
+		return ""
+	}
+	var typed struct {
+		Type string `json:"$type"`
+	}
+	if err := json.Unmarshal(embed, &typed); err != nil {
+		return ""
+	}
+	return typed.Type
+}
+
+// embedMedia returns the media of a quote with media, or the embed itself otherwise
+func embedMedia(embed json.RawMessage) json.RawMessage {
+	if !strings.HasPrefix(embedType(embed), "app.bsky.embed.recordWithMedia") {
+		return embed
+	}
+	var withMedia struct {
+		Media json.RawMessage `json:"media"`
+	}
+	if err := json.Unmarshal(embed, &withMedia); err != nil {
+		return embed
+	}
+	return withMedia.Media
+}
+
+// Video returns the post's video, including one alongside a quoted post, or nil
+// if it has none. A video still being processed has no view yet, so it is
+// taken from the record with an empty playlist.
+func (p *PostView) Video() *VideoView {
+	view := embedMedia(p.Embed)
+	if embedType(view) == "app.bsky.embed.video#view" {
+		var video VideoView
+		if err := json.Unmarshal(view, &video); err == nil {
+			return &video
+		}
+	}
+
+	// Fall back to the record, which has the video as soon as it's posted
+	record := embedMedia(p.Record.Embed)
+	if embedType(record) != "app.bsky.embed.video" {
+		return nil
+	}
+	var video struct {
+		Alt         string       `json:"alt,omitempty"`
+		AspectRatio *AspectRatio `json:"aspectRatio,omitempty"`
+	}
+	if err := json.Unmarshal(record, &video); err != nil {
+		return nil
+	}
+	return &VideoView{Alt: video.Alt, AspectRatio: video.AspectRatio}
+}
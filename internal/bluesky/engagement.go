@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import "fmt"
+
+// maxListRecords is the most records listRecords returns in one page
+const maxListRecords = 100
+
+// Engagement returns how much interaction a post got
+func (p *PostView) Engagement() int {
+	return p.LikeCount + p.RepostCount + p.ReplyCount + p.QuoteCount
+}
+
+// RecentPosts fetches the account's last n posts, newest first, with their engagement counts
+func RecentPosts(token *DIDResponse, n int) ([]PostView, error) {
+	var uris []string
+	cursor := ""
+	for len(uris) < n {
+		page, err := ListRecords(token, token.DID, "app.bsky.feed.post", cursor, min(n-len(uris), maxListRecords))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list posts: %w", err)
+		}
+		for _, record := range page.Records {
+			uris = append(uris, record.URI)
+		}
+		if page.Cursor == "" || len(page.Records) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	return GetPosts(token, uris)
+}
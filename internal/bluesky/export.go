@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExportedPost is one line of a JSONL export. Its fields are a superset of
+// ImportedPost, so an export can be imported back.
+type ExportedPost struct {
+	URI       string          `json:"uri"`
+	CID       string          `json:"cid"`
+	RKey      string          `json:"rkey"`
+	Text      string          `json:"text"`
+	CreatedAt string          `json:"createdAt"`
+	Facets    []Facet         `json:"facets,omitempty"`
+	Langs     []string        `json:"langs,omitempty"`
+	Reply     *ReplyRef       `json:"reply,omitempty"`
+	Embed     json.RawMessage `json:"embed,omitempty"`
+	// Blobs lists the CIDs of media referenced by the embed. Blobs are stored
+	// outside the record, so they have to be re-uploaded on import.
+	Blobs []string `json:"blobs,omitempty"`
+}
+
+// ExportPosts pages through every post in the authenticated user's repo, calling fn for each
+func ExportPosts(token *DIDResponse, fn func(ExportedPost) error) error {
+	cursor := ""
+	for {
+		page, err := ListRecords(token, token.DID, "app.bsky.feed.post", cursor, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list posts: %w", err)
+		}
+
+		for _, record := range page.Records {
+			var post PostRecord
+			if err := json.Unmarshal(record.Value, &post); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", record.URI, err)
+			}
+
+			uri, err := ParseATURI(record.URI)
+			if err != nil {
+				return err
+			}
+
+			exported := ExportedPost{
+				URI:       record.URI,
+				CID:       record.CID,
+				RKey:      uri.RKey,
+				Text:      post.Text,
+				CreatedAt: post.CreatedAt,
+				Facets:    post.Facets,
+				Langs:     post.Langs,
+				Reply:     post.Reply,
+				Embed:     post.Embed,
+				Blobs:     findBlobs(post.Embed),
+			}
+			if err := fn(exported); err != nil {
+				return err
+			}
+		}
+
+		if page.Cursor == "" || len(page.Records) == 0 {
+			return nil
+		}
+		cursor = page.Cursor
+	}
+}
+
+// findBlobs collects the CIDs of every blob referenced anywhere in an embed
+func findBlobs(embed json.RawMessage) []string {
+	if len(embed) == 0 {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(embed, &value); err != nil {
+		return nil
+	}
+
+	var blobs []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if v["$type"] == "blob" {
+				if ref, ok := v["ref"].(map[string]interface{}); ok {
+					if link, ok := ref["$link"].(string); ok {
+						blobs = append(blobs, link)
+					}
+				}
+				return
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+
+	return blobs
+}
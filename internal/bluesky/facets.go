@@ -0,0 +1,286 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alexisbcz/yabc/internal/richtext"
+)
+
+// Facet annotates a byte range of a post's text with rich-text features
+type Facet struct {
+	Index    ByteSlice      `json:"index"`
+	Features []FacetFeature `json:"features"`
+}
+
+// ByteSlice is a range of UTF-8 bytes in a post's text, end exclusive
+type ByteSlice struct {
+	ByteStart int `json:"byteStart"`
+	ByteEnd   int `json:"byteEnd"`
+}
+
+// FacetFeature is a single feature (link, mention or tag) attached to a facet
+type FacetFeature struct {
+	Type string `json:"$type"`
+	URI  string `json:"uri,omitempty"`
+	DID  string `json:"did,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+const (
+	FacetLink    = "app.bsky.richtext.facet#link"
+	FacetMention = "app.bsky.richtext.facet#mention"
+	FacetTag     = "app.bsky.richtext.facet#tag"
+)
+
+// LinkFacets turns the links of a text into link facets
+func LinkFacets(links []richtext.Link) []Facet {
+	var facets []Facet
+	for _, link := range links {
+		facets = append(facets, Facet{
+			Index:    ByteSlice{ByteStart: link.Start, ByteEnd: link.End},
+			Features: []FacetFeature{{Type: FacetLink, URI: link.URI}},
+		})
+	}
+	return facets
+}
+
+// maxTagLength is the most characters a hashtag can have, not counting the #
+const maxTagLength = 64
+
+// BuildFacets finds the bare URLs, hashtags and mentions in text and returns
+// facets for them, so they show up as links, tappable tags and mentions rather
+// than plain text. Mentioned handles are resolved to DIDs over the network.
+func BuildFacets(text string) []Facet {
+	return buildFacets(text, ResolveHandle, true)
+}
+
+// buildFacets is BuildFacets with the function mentioned handles are resolved
+// with, leaving bare URLs as plain text unless linkify is set
+func buildFacets(text string, resolve func(handle string) (string, error), linkify bool) []Facet {
+	facets := tagFacets(text)
+	if linkify {
+		facets = mergeFacets(LinkFacets(richtext.DetectLinks(text)), facets)
+	}
+	return mergeFacets(facets, mentionFacets(text, resolve))
+}
+
+// mentionPattern matches @handle at the start of the text, after whitespace or after an opening parenthesis
+var mentionPattern = regexp.MustCompile(`(?:^|[\s(])@([a-zA-Z0-9.-]+)`)
+
+// mention is an @handle in a text, starting at the byte offset of its @
+type mention struct {
+	start  int
+	handle string
+}
+
+// findMentions returns the @handles in text. Handles need a dot, so "@home" isn't one.
+func findMentions(text string) []mention {
+	var mentions []mention
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		// A period ending the sentence isn't part of the handle
+		handle := strings.TrimRight(text[m[2]:m[3]], ".-")
+		if !strings.Contains(handle, ".") || strings.HasPrefix(handle, ".") {
+			continue
+		}
+		mentions = append(mentions, mention{start: m[2] - 1, handle: handle})
+	}
+	return mentions
+}
+
+// reportedMentions are the handles UnresolvedMentions returned, which the
+// command has warned about already, so posting doesn't warn about them again
+var reportedMentions = struct {
+	sync.Mutex
+	handles map[string]bool
+}{handles: map[string]bool{}}
+
+// UnresolvedMentions returns the handles @mentioned in text that don't resolve
+// to an account, once each. Posting leaves them as plain text, so checking
+// first catches mistyped mentions. The caller is expected to report them.
+func UnresolvedMentions(text string) []string {
+	var unresolved []string
+	seen := map[string]bool{}
+	for _, m := range findMentions(text) {
+		handle := strings.ToLower(m.handle)
+		if seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		if _, err := ResolveHandle(handle); err != nil {
+			slog.Debug("Mentioned handle doesn't resolve", "handle", handle, "error", err)
+			unresolved = append(unresolved, m.handle)
+
+			reportedMentions.Lock()
+			reportedMentions.handles[handle] = true
+			reportedMentions.Unlock()
+		}
+	}
+	return unresolved
+}
+
+// mentionFacets returns mention facets for the @handles in text. A handle that
+// doesn't resolve stays plain text rather than failing the post, with a
+// warning unless UnresolvedMentions already reported it.
+func mentionFacets(text string, resolve func(handle string) (string, error)) []Facet {
+	var facets []Facet
+	for _, m := range findMentions(text) {
+		did, err := resolve(m.handle)
+		if err != nil {
+			reportedMentions.Lock()
+			reported := reportedMentions.handles[strings.ToLower(m.handle)]
+			reportedMentions.Unlock()
+			if reported {
+				slog.Debug("Could not resolve mentioned handle, leaving it as plain text", "handle", m.handle, "error", err)
+			} else {
+				slog.Warn("Could not resolve mentioned handle, leaving it as plain text", "handle", m.handle, "error", err)
+			}
+			continue
+		}
+
+		// The facet covers the @ too
+		facets = append(facets, Facet{
+			Index:    ByteSlice{ByteStart: m.start, ByteEnd: m.start + 1 + len(m.handle)},
+			Features: []FacetFeature{{Type: FacetMention, DID: did}},
+		})
+	}
+	return facets
+}
+
+// tagFacets returns tag facets for the hashtags in text
+func tagFacets(text string) []Facet {
+	var facets []Facet
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		// A tag starts with # at the start of the text or after whitespace
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+		if (r != '#' && r != '＃') || (i > 0 && !unicode.IsSpace(prev)) {
+			i += size
+			continue
+		}
+
+		start := i
+		end := i + size
+		for end < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if unicode.IsSpace(next) {
+				break
+			}
+			end += nextSize
+		}
+
+		// Punctuation ending a sentence isn't part of the tag
+		tag := strings.TrimRightFunc(text[start+size:end], unicode.IsPunct)
+		i = end
+		if !isTag(tag) {
+			continue
+		}
+
+		facets = append(facets, Facet{
+			Index:    ByteSlice{ByteStart: start, ByteEnd: start + size + len(tag)},
+			Features: []FacetFeature{{Type: FacetTag, Tag: tag}},
+		})
+	}
+	return facets
+}
+
+// isTag reports whether the text after a # makes a tag: not empty, not only
+// digits like "#1", and not too long
+func isTag(tag string) bool {
+	if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+		return false
+	}
+	return strings.ContainsFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) })
+}
+
+// mergeFacets adds the detected facets to the given ones, skipping any that
+// overlap a given facet, which was set on purpose and wins
+func mergeFacets(given, detected []Facet) []Facet {
+	merged := given
+	for _, facet := range detected {
+		overlaps := false
+		for _, existing := range given {
+			if facet.Index.ByteStart < existing.Index.ByteEnd && existing.Index.ByteStart < facet.Index.ByteEnd {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			merged = append(merged, facet)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Index.ByteStart < merged[j].Index.ByteStart })
+	return merged
+}
+
+// SanitizeFacets drops facets whose byte range doesn't line up with text.
+// Facets coming from other tools may have been computed against a differently
+// normalized string, and a single bad range makes the server reject the whole record.
+func SanitizeFacets(text string, facets []Facet) []Facet {
+	var valid []Facet
+	for i, facet := range facets {
+		if reason := checkFacet(text, facet); reason != "" {
+			slog.Warn("Dropping facet",
+				"index", i,
+				"byteStart", facet.Index.ByteStart,
+				"byteEnd", facet.Index.ByteEnd,
+				"reason", reason,
+			)
+			continue
+		}
+		valid = append(valid, facet)
+	}
+	return valid
+}
+
+// checkFacet returns why a facet is invalid for text, or "" if it is fine
+func checkFacet(text string, facet Facet) string {
+	start, end := facet.Index.ByteStart, facet.Index.ByteEnd
+	switch {
+	case len(facet.Features) == 0:
+		return "facet has no features"
+	case start < 0 || end > len(text):
+		return "byte range is outside the text"
+	case start >= end:
+		return "byte range is empty"
+	case !isRuneBoundary(text, start) || !isRuneBoundary(text, end):
+		return "byte range splits a multibyte character"
+	}
+
+	slice := text[start:end]
+	if strings.TrimSpace(slice) == "" {
+		return "byte range only covers whitespace"
+	}
+
+	// The covered text should look like what the feature claims to be
+	for _, feature := range facet.Features {
+		switch feature.Type {
+		case FacetTag:
+			if !strings.EqualFold(strings.TrimPrefix(slice, "#"), feature.Tag) {
+				return "byte range covers " + quote(slice) + ", not tag #" + feature.Tag
+			}
+		case FacetMention:
+			if !strings.HasPrefix(slice, "@") {
+				return "byte range covers " + quote(slice) + ", not a mention"
+			}
+		}
+	}
+
+	return ""
+}
+
+// isRuneBoundary reports whether i falls between two UTF-8 characters of s
+func isRuneBoundary(s string, i int) bool {
+	return i == len(s) || utf8.RuneStart(s[i])
+}
+
+func quote(s string) string {
+	return `"` + s + `"`
+}
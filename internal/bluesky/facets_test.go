@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestMentionsResolvedOnce(t *testing.T) {
+	quiet(t)
+
+	var mu sync.Mutex
+	lookups := map[string]int{}
+	testPDS(t, func(w http.ResponseWriter, r *http.Request) {
+		handle := r.URL.Query().Get("handle")
+		mu.Lock()
+		lookups[handle]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if handle != "alice.once.test" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"InvalidRequest","message":"Unable to resolve handle"}`)
+			return
+		}
+		fmt.Fprint(w, `{"did":"did:plc:alice"}`)
+	})
+
+	// Checking mentions before posting, then posting, as "yabc posts create" does
+	text := "Hi @alice.once.test and @nobody.once.test"
+	unresolved := UnresolvedMentions(text)
+	if len(unresolved) != 1 || unresolved[0] != "nobody.once.test" {
+		t.Fatalf("got unresolved %v, want [nobody.once.test]", unresolved)
+	}
+	facets := mentionFacets(text, ResolveHandle)
+	if len(facets) != 1 || facets[0].Features[0].DID != "did:plc:alice" {
+		t.Errorf("got facets %+v, want one mentioning did:plc:alice", facets)
+	}
+
+	for _, handle := range []string{"alice.once.test", "nobody.once.test"} {
+		if lookups[handle] != 1 {
+			t.Errorf("%s looked up %d times, want once", handle, lookups[handle])
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ProfileViewBasic is the author information embedded in post views
+type ProfileViewBasic struct {
+	DID         string `json:"did"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// PostRecord is the app.bsky.feed.post record as stored in a repo
+type PostRecord struct {
+	Type      string          `json:"$type"`
+	Text      string          `json:"text"`
+	CreatedAt string          `json:"createdAt"`
+	Reply     *ReplyRef       `json:"reply,omitempty"`
+	Facets    []Facet         `json:"facets,omitempty"`
+	Langs     []string        `json:"langs,omitempty"`
+	Embed     json.RawMessage `json:"embed,omitempty"`
+}
+
+// PostView is a post as returned by the app view, with engagement counts
+type PostView struct {
+	URI         string           `json:"uri"`
+	CID         string           `json:"cid"`
+	Author      ProfileViewBasic `json:"author"`
+	Record      PostRecord       `json:"record"`
+	ReplyCount  int              `json:"replyCount"`
+	RepostCount int              `json:"repostCount"`
+	LikeCount   int              `json:"likeCount"`
+	QuoteCount  int              `json:"quoteCount"`
+	IndexedAt   string           `json:"indexedAt"`
+	// Embed is the hydrated view of the record's embed, e.g. app.bsky.embed.video#view
+	Embed json.RawMessage `json:"embed,omitempty"`
+	// Viewer is how the logged-in account has engaged with the post
+	Viewer *ViewerState `json:"viewer,omitempty"`
+}
+
+// ViewerState holds the URIs of the logged-in account's like and repost of a post, if any
+type ViewerState struct {
+	Like   string `json:"like,omitempty"`
+	Repost string `json:"repost,omitempty"`
+}
+
+// ThreadViewPost is a node of a post thread. Deleted or blocked posts have a
+// different $type and an empty Post.
+type ThreadViewPost struct {
+	Type    string           `json:"$type"`
+	Post    PostView         `json:"post"`
+	Replies []ThreadViewPost `json:"replies,omitempty"`
+}
+
+// ThreadResponse is the response from getPostThread
+type ThreadResponse struct {
+	Thread ThreadViewPost `json:"thread"`
+}
+
+const threadViewPostType = "app.bsky.feed.defs#threadViewPost"
+
+// IsPost reports whether the node holds a visible post rather than a deleted or blocked one
+func (t *ThreadViewPost) IsPost() bool {
+	return t.Type == threadViewPostType
+}
+
+// GetPostThread fetches a post and its replies, up to depth levels deep
+func GetPostThread(token *DIDResponse, uri string, depth int) (*ThreadResponse, error) {
+	query := url.Values{}
+	query.Set("uri", uri)
+	query.Set("depth", strconv.Itoa(depth))
+
+	var thread ThreadResponse
+	if err := xrpcGet(token, "app.bsky.feed.getPostThread", query, &thread); err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
+}
+
+// maxGetPosts is the most posts getPosts returns in one call
+const maxGetPosts = 25
+
+// GetPosts fetches the views of posts by URI, batching requests as needed.
+// Posts that were deleted or can't be seen are missing from the result.
+func GetPosts(token *DIDResponse, uris []string) ([]PostView, error) {
+	var posts []PostView
+	for start := 0; start < len(uris); start += maxGetPosts {
+		end := min(start+maxGetPosts, len(uris))
+
+		query := url.Values{"uris": uris[start:end]}
+		var resp struct {
+			Posts []PostView `json:"posts"`
+		}
+		if err := xrpcGet(token, "app.bsky.feed.getPosts", query, &resp); err != nil {
+			return posts, fmt.Errorf("failed to fetch posts: %w", err)
+		}
+		posts = append(posts, resp.Posts...)
+	}
+	return posts, nil
+}
+
+// Like is a single like on a post
+type Like struct {
+	Actor     ProfileViewBasic `json:"actor"`
+	CreatedAt string           `json:"createdAt"`
+}
+
+// LikesResponse is a page of likes on a post
+type LikesResponse struct {
+	URI    string `json:"uri"`
+	Likes  []Like `json:"likes"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// GetLikes fetches a page of likes on a post
+func GetLikes(token *DIDResponse, uri, cursor string, limit int) (*LikesResponse, error) {
+	query := url.Values{}
+	query.Set("uri", uri)
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var likes LikesResponse
+	if err := xrpcGet(token, "app.bsky.feed.getLikes", query, &likes); err != nil {
+		return nil, err
+	}
+
+	return &likes, nil
+}
+
+// CountLikes pages through every like on a post and returns the total
+func CountLikes(token *DIDResponse, uri string) (int, error) {
+	count, cursor := 0, ""
+	for {
+		page, err := GetLikes(token, uri, cursor, 100)
+		if err != nil {
+			return 0, err
+		}
+		count += len(page.Likes)
+
+		if page.Cursor == "" || len(page.Likes) == 0 {
+			return count, nil
+		}
+		cursor = page.Cursor
+	}
+}
+
+// FeedReason explains why a post is in a feed, e.g. because someone reposted it
+type FeedReason struct {
+	Type      string           `json:"$type"`
+	By        ProfileViewBasic `json:"by"`
+	IndexedAt string           `json:"indexedAt"`
+}
+
+// FeedViewPost is a single item of a feed
+type FeedViewPost struct {
+	Post   PostView    `json:"post"`
+	Reason *FeedReason `json:"reason,omitempty"`
+}
+
+// IndexedAt is when the item entered the feed: the repost time for reposts, the post's otherwise
+func (f *FeedViewPost) IndexedAt() string {
+	if f.Reason != nil && f.Reason.IndexedAt != "" {
+		return f.Reason.IndexedAt
+	}
+	return f.Post.IndexedAt
+}
+
+// FeedResponse is a page of a feed
+type FeedResponse struct {
+	Feed   []FeedViewPost `json:"feed"`
+	Cursor string         `json:"cursor,omitempty"`
+}
+
+// GetTimeline fetches a page of the authenticated user's home timeline
+func GetTimeline(token *DIDResponse, cursor string, limit int) (*FeedResponse, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var feed FeedResponse
+	if err := xrpcGet(token, "app.bsky.feed.getTimeline", query, &feed); err != nil {
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+// GetAuthorFeed fetches a page of an actor's posts and reposts, newest first
+func GetAuthorFeed(token *DIDResponse, actor, cursor string, limit int) (*FeedResponse, error) {
+	query := url.Values{}
+	query.Set("actor", actor)
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var feed FeedResponse
+	if err := xrpcGet(token, "app.bsky.feed.getAuthorFeed", query, &feed); err != nil {
+		return nil, err
+	}
+
+	return &feed, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+)
+
+// followCollection holds the follow records of a repo, one per followed account
+const followCollection = "app.bsky.graph.follow"
+
+var (
+	// ErrAlreadyFollowing is returned along with the existing follow when following an account followed before
+	ErrAlreadyFollowing = errors.New("already following")
+	// ErrNotFollowing is returned when unfollowing an account that isn't followed
+	ErrNotFollowing = errors.New("not following")
+	// ErrFollowSelf is returned when following or unfollowing your own account
+	ErrFollowSelf = errors.New("you can't follow yourself")
+)
+
+// Follow follows an account, given by handle or DID, creating an
+// app.bsky.graph.follow record. The returned URI is the follow's. An account
+// followed before isn't followed twice: the existing follow is returned with
+// ErrAlreadyFollowing.
+func Follow(token *DIDResponse, actor string) (*PostCreateResponse, error) {
+	did, err := resolveActorDID(actor)
+	if err != nil {
+		return nil, err
+	}
+	if did == token.DID {
+		return nil, ErrFollowSelf
+	}
+
+	existing, err := findSubjectRecord(token, followCollection, did)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &PostCreateResponse{URI: existing.URI, CID: existing.CID}, ErrAlreadyFollowing
+	}
+
+	return CreateRecord(token, followCollection, map[string]interface{}{
+		"$type":     followCollection,
+		"subject":   did,
+		"createdAt": getCurrentTime(),
+	})
+}
+
+// Unfollow unfollows an account, given by handle or DID, deleting the follow
+// record found in the user's repo. It returns the URI of the deleted follow,
+// or ErrNotFollowing if there was none.
+func Unfollow(token *DIDResponse, actor string) (string, error) {
+	did, err := resolveActorDID(actor)
+	if err != nil {
+		return "", err
+	}
+	if did == token.DID {
+		return "", ErrFollowSelf
+	}
+
+	existing, err := findSubjectRecord(token, followCollection, did)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		return "", ErrNotFollowing
+	}
+
+	uri, err := ParseATURI(existing.URI)
+	if err != nil {
+		return "", err
+	}
+	if err := DeleteRecord(token, followCollection, uri.RKey); err != nil {
+		return "", fmt.Errorf("failed to delete follow %s: %w", existing.URI, err)
+	}
+	return existing.URI, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxGIFHeader is how much of a GIF is fetched to read its dimensions, which
+// come right after the signature
+const maxGIFHeader = 1 << 10
+
+// GIFAttachment is a Tenor GIF to attach to a post
+type GIFAttachment struct {
+	// URL is a tenor.com/view page or a media.tenor.com GIF
+	URL string
+	Alt string
+}
+
+// tenorGIF is a Tenor GIF's media URL along with what Bluesky shows with it
+type tenorGIF struct {
+	MediaURL    string
+	Width       int
+	Height      int
+	Description string
+}
+
+// IsTenorURL reports whether a URL is a Tenor GIF, either its tenor.com/view
+// page or the GIF itself on media.tenor.com
+func IsTenorURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case isTenorMediaHost(host):
+		return strings.HasSuffix(strings.ToLower(u.Path), ".gif")
+	case host == "tenor.com" || host == "www.tenor.com":
+		return strings.Contains(u.Path, "/view/")
+	}
+	return false
+}
+
+// isTenorMediaHost reports whether a host serves Tenor's GIFs, e.g. media.tenor.com or media1.tenor.com
+func isTenorMediaHost(host string) bool {
+	name, ok := strings.CutSuffix(host, ".tenor.com")
+	return ok && strings.HasPrefix(name, "media") && strings.Trim(name[len("media"):], "0123456789") == ""
+}
+
+// resolveTenorGIF finds a Tenor GIF's media URL and dimensions. A tenor.com
+// page names its GIF in its OpenGraph tags; a GIF's dimensions are read from
+// its header.
+func resolveTenorGIF(rawURL string) (*tenorGIF, error) {
+	if !IsTenorURL(rawURL) {
+		return nil, fmt.Errorf("not a Tenor GIF URL: %s", rawURL)
+	}
+
+	gif := &tenorGIF{MediaURL: rawURL}
+	if u, _ := url.Parse(rawURL); !isTenorMediaHost(strings.ToLower(u.Hostname())) {
+		card, meta, err := fetchPageMeta(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		if card.ThumbURL == "" || !IsTenorURL(card.ThumbURL) {
+			return nil, fmt.Errorf("no GIF found on %s", rawURL)
+		}
+		gif.MediaURL, gif.Description = card.ThumbURL, card.Description
+		gif.Width, _ = strconv.Atoi(meta["og:image:width"])
+		gif.Height, _ = strconv.Atoi(meta["og:image:height"])
+	}
+
+	// Bluesky plays the GIF from the URL, so only the bare URL is kept
+	if u, err := url.Parse(gif.MediaURL); err == nil {
+		u.RawQuery, u.Fragment = "", ""
+		gif.MediaURL = u.String()
+	}
+
+	if gif.Width <= 0 || gif.Height <= 0 {
+		header, err := fetchURL(gif.MediaURL, maxGIFHeader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", gif.MediaURL, err)
+		}
+		config, format, err := image.DecodeConfig(bytes.NewReader(header))
+		if err != nil || format != "gif" {
+			return nil, fmt.Errorf("%s is not a GIF", gif.MediaURL)
+		}
+		gif.Width, gif.Height = config.Width, config.Height
+	}
+	return gif, nil
+}
+
+// buildGIFEmbed builds the app.bsky.embed.external embed Bluesky plays as an
+// animated GIF: a Tenor media URL carrying its height and width as the hh and
+// ww parameters, with the alt text as description and a still as thumbnail
+func buildGIFEmbed(token *DIDResponse, attachment GIFAttachment) (map[string]interface{}, error) {
+	gif, err := resolveTenorGIF(attachment.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	card := &ExternalEmbed{
+		URI:         tenorPlaybackURL(gif.MediaURL, gif.Width, gif.Height),
+		Title:       firstNonEmpty(gif.Description, gifTitle(gif.MediaURL)),
+		Description: gifDescription(attachment.Alt),
+		ThumbURL:    gif.MediaURL,
+	}
+	return buildExternalEmbed(token, card, true), nil
+}
+
+// tenorPlaybackURL adds the dimensions Bluesky sizes the GIF player with
+func tenorPlaybackURL(mediaURL string, width, height int) string {
+	return fmt.Sprintf("%s?hh=%d&ww=%d", mediaURL, height, width)
+}
+
+// gifTitle derives a title from a GIF's file name, e.g. "Happy Dance" for happy-dance.gif
+func gifTitle(mediaURL string) string {
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return "GIF"
+	}
+	name := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	if name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' }), " "); name == "" {
+		return "GIF"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// gifDescription is the description Bluesky's apps give GIFs, holding their alt text
+func gifDescription(alt string) string {
+	if alt == "" {
+		return ""
+	}
+	return "Alt: " + alt
+}
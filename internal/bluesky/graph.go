@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProfileViewDetailed is a full profile, with follower and post counts
+type ProfileViewDetailed struct {
+	DID            string `json:"did"`
+	Handle         string `json:"handle"`
+	DisplayName    string `json:"displayName,omitempty"`
+	Description    string `json:"description,omitempty"`
+	FollowersCount int    `json:"followersCount"`
+	FollowsCount   int    `json:"followsCount"`
+	PostsCount     int    `json:"postsCount"`
+}
+
+// GetProfile fetches the profile of an actor, given as a handle or DID
+func GetProfile(token *DIDResponse, actor string) (*ProfileViewDetailed, error) {
+	var profile ProfileViewDetailed
+	if err := xrpcGet(token, "app.bsky.actor.getProfile", url.Values{"actor": {actor}}, &profile); err != nil {
+		return nil, fmt.Errorf("failed to get profile of %s: %w", actor, err)
+	}
+	return &profile, nil
+}
+
+// FollowsResponse is a page of the accounts an actor follows, or of its followers
+type FollowsResponse struct {
+	Subject   ProfileViewBasic   `json:"subject"`
+	Follows   []ProfileViewBasic `json:"follows,omitempty"`
+	Followers []ProfileViewBasic `json:"followers,omitempty"`
+	Cursor    string             `json:"cursor,omitempty"`
+}
+
+// GetFollows fetches a page of the accounts an actor follows
+func GetFollows(token *DIDResponse, actor, cursor string, limit int) (*FollowsResponse, error) {
+	return getFollowList(token, "app.bsky.graph.getFollows", actor, cursor, limit)
+}
+
+// GetFollowers fetches a page of the accounts following an actor
+func GetFollowers(token *DIDResponse, actor, cursor string, limit int) (*FollowsResponse, error) {
+	return getFollowList(token, "app.bsky.graph.getFollowers", actor, cursor, limit)
+}
+
+func getFollowList(token *DIDResponse, method, actor, cursor string, limit int) (*FollowsResponse, error) {
+	query := url.Values{}
+	query.Set("actor", actor)
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var resp FollowsResponse
+	if err := xrpcGet(token, method, query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// resolveActorDID resolves an account given by handle or DID to its DID
+func resolveActorDID(actor string) (string, error) {
+	actor = strings.TrimPrefix(strings.TrimSpace(actor), "@")
+	if strings.HasPrefix(actor, "did:") {
+		return actor, nil
+	}
+	return ResolveHandle(actor)
+}
+
+// findSubjectRecord looks through a collection of the user's repo whose
+// records point at an account, like follows or blocks, for one of the account
+// with the given DID, returning nil if there is none. Following or blocking
+// several times leaves several records, of which the first is returned.
+func findSubjectRecord(token *DIDResponse, collection, did string) (*RecordResponse, error) {
+	cursor := ""
+	for {
+		page, err := ListRecords(token, token.DID, collection, cursor, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s records: %w", collection, err)
+		}
+		for i, record := range page.Records {
+			var value struct {
+				Subject string `json:"subject"`
+			}
+			if err := json.Unmarshal(record.Value, &value); err != nil {
+				slog.Debug("Skipping unreadable record", "uri", record.URI, "error", err)
+				continue
+			}
+			if value.Subject == did {
+				return &page.Records[i], nil
+			}
+		}
+
+		if page.Cursor == "" || len(page.Records) == 0 {
+			return nil, nil
+		}
+		cursor = page.Cursor
+		time.Sleep(pagePacing)
+	}
+}
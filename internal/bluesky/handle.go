@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrHandleVerification means a custom domain doesn't point at the account's DID
+var ErrHandleVerification = errors.New("handle verification failed")
+
+// ErrHandleTaken means another account already uses the handle
+var ErrHandleTaken = errors.New("handle is already taken")
+
+// VerifyHandle checks that a custom domain handle points at did, through either
+// a _atproto DNS TXT record or https://<handle>/.well-known/atproto-did
+func VerifyHandle(handle, did string) error {
+	records, err := net.LookupTXT("_atproto." + handle)
+	if err != nil {
+		slog.Debug("No _atproto TXT record", "handle", handle, "error", err)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == "did="+did {
+			return nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(requestContext, "GET", fmt.Sprintf("https://%s/.well-known/atproto-did", handle), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req, cancel := withTimeout(req, requestTimeout)
+	defer cancel()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: no _atproto TXT record for %s and the well-known file is unreachable: %v", ErrHandleVerification, handle, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: no _atproto TXT record for %s and no well-known file (status %d)", ErrHandleVerification, handle, resp.StatusCode)
+	}
+	if found := strings.TrimSpace(string(body)); found != did {
+		return fmt.Errorf("%w: %s points at %q, not %s", ErrHandleVerification, handle, found, did)
+	}
+	return nil
+}
+
+// UpdateHandle changes the account's handle, verifying custom domains first
+func UpdateHandle(token *DIDResponse, handle string) error {
+	description, err := DescribeServer()
+	if err != nil {
+		return err
+	}
+
+	// Handles under the PDS's own domains are managed by the PDS itself
+	if !hasAnySuffix(handle, description.AvailableUserDomains) {
+		if err := VerifyHandle(handle, token.DID); err != nil {
+			return err
+		}
+	}
+
+	err = xrpcPost(token, "com.atproto.identity.updateHandle", map[string]string{"handle": handle}, nil)
+	var xrpcErr *XRPCError
+	if errors.As(err, &xrpcErr) {
+		switch xrpcErr.Name {
+		case "HandleNotAvailable":
+			return ErrHandleTaken
+		case "InvalidHandle", "UnsupportedDomain":
+			return fmt.Errorf("invalid handle: %s", xrpcErr.Message)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update handle: %w", err)
+	}
+	return nil
+}
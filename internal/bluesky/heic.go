@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+)
+
+// heicMimeType is the MIME type of HEIC/HEIF photos, which Bluesky doesn't accept
+const heicMimeType = "image/heic"
+
+// heicBrands are the ftyp brands of HEIC/HEIF files, as written by iPhones and others
+var heicBrands = []string{"heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1"}
+
+// isHEIC reports whether data starts like a HEIC/HEIF file: an ftyp box with a HEIF brand
+func isHEIC(data []byte) bool {
+	if len(data) < 12 || !bytes.Equal(data[4:8], []byte("ftyp")) {
+		return false
+	}
+	for _, brand := range heicBrands {
+		if bytes.Equal(data[8:12], []byte(brand)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrNoHEICConverter is returned when a HEIC photo is attached but no tool to convert it is installed
+var ErrNoHEICConverter = errors.New("converting HEIC images needs heif-convert (libheif), sips (macOS) or ImageMagick, none of which was found")
+
+// heicConverters are the command-line tools that can turn HEIC into JPEG, most
+// common first. There is no HEIC decoder in Go without cgo, so yabc relies on these.
+var heicConverters = []struct {
+	name string
+	args func(in, out string) []string
+}{
+	{"heif-convert", func(in, out string) []string { return []string{"-q", "90", in, out} }},
+	{"sips", func(in, out string) []string { return []string{"-s", "format", "jpeg", in, "--out", out} }},
+	{"magick", func(in, out string) []string { return []string{in, out} }},
+}
+
+// convertHEIC converts a HEIC photo to JPEG with the first converter found
+func convertHEIC(imagePath string) ([]byte, error) {
+	for _, converter := range heicConverters {
+		bin, err := exec.LookPath(converter.name)
+		if err != nil {
+			continue
+		}
+
+		file, err := tempfiles.Create("yabc-*.jpg")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		file.Close()
+		defer tempfiles.Remove(file.Name())
+
+		slog.Info("Converting HEIC image to JPEG", "path", imagePath, "converter", converter.name)
+		if output, err := exec.Command(bin, converter.args(imagePath, file.Name())...).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to convert %s with %s: %w: %s", imagePath, converter.name, err, bytes.TrimSpace(output))
+		}
+
+		data, err := os.ReadFile(file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read converted image: %w", err)
+		}
+		return data, nil
+	}
+	return nil, ErrNoHEICConverter
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// plcDirectory resolves did:plc identifiers to their DID documents
+const plcDirectory = "https://plc.directory"
+
+// resolvedHandles caches handle to DID lookups for the lifetime of the process,
+// and the handles the server said don't resolve, so that checking mentions
+// before posting doesn't mean looking them up twice
+var resolvedHandles = struct {
+	sync.Mutex
+	dids   map[string]string
+	failed map[string]error
+}{dids: map[string]string{}, failed: map[string]error{}}
+
+// ResolveHandle returns the DID a handle points to
+func ResolveHandle(handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+
+	resolvedHandles.Lock()
+	did, ok := resolvedHandles.dids[handle]
+	failed := resolvedHandles.failed[handle]
+	resolvedHandles.Unlock()
+	if ok {
+		return did, nil
+	}
+	if failed != nil {
+		return "", failed
+	}
+
+	var resolved struct {
+		DID string `json:"did"`
+	}
+	query := url.Values{"handle": {handle}}
+	if err := xrpcGet(nil, "com.atproto.identity.resolveHandle", query, &resolved); err != nil {
+		err = fmt.Errorf("failed to resolve handle %s: %w", handle, err)
+
+		// Only remember the server's answer, not a network error that may not happen again
+		var xrpcErr *XRPCError
+		if errors.As(err, &xrpcErr) {
+			resolvedHandles.Lock()
+			resolvedHandles.failed[handle] = err
+			resolvedHandles.Unlock()
+		}
+		return "", err
+	}
+
+	resolvedHandles.Lock()
+	resolvedHandles.dids[handle] = resolved.DID
+	resolvedHandles.Unlock()
+	return resolved.DID, nil
+}
+
+// Identity is an account's handle resolved to its DID and PDS
+type Identity struct {
+	DID    string
+	Handle string
+	PDS    string
+}
+
+// ResolveIdentity resolves a handle to its DID, then the DID to the PDS hosting
+// the account. A DID can be given instead of a handle.
+func ResolveIdentity(handle string) (*Identity, error) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+
+	did := handle
+	if !strings.HasPrefix(handle, "did:") {
+		var err error
+		if did, err = ResolveHandle(handle); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := resolveDID(did)
+	if err != nil {
+		return nil, err
+	}
+	pds := doc.PDSEndpoint()
+	if pds == "" {
+		return nil, fmt.Errorf("%s has no PDS in its DID document", did)
+	}
+	if did == handle {
+		handle = doc.Handle()
+	}
+
+	return &Identity{DID: did, Handle: handle, PDS: pds}, nil
+}
+
+// useAccountPDS points yabc at the PDS hosting the account, so accounts on
+// self-hosted PDSes work without --pds. Emails can't be resolved, and neither
+// can every handle, in which case the current PDS is kept.
+func useAccountPDS(identifier string) {
+	if identifier == "" || strings.Contains(identifier, "@") {
+		return
+	}
+
+	identity, err := ResolveIdentity(identifier)
+	if err != nil {
+		slog.Debug("Could not find the account's PDS, using the default", "identifier", identifier, "error", err)
+		return
+	}
+	if err := SetPDS(identity.PDS); err != nil {
+		slog.Debug("Ignoring invalid PDS from DID document", "pds", identity.PDS, "error", err)
+		return
+	}
+	slog.Debug("Using the account's PDS", "pds", PDS())
+}
+
+// resolveDID fetches the DID document of a did:plc or did:web identifier
+func resolveDID(did string) (*DIDDoc, error) {
+	var docURL string
+	switch {
+	case strings.HasPrefix(did, "did:plc:"):
+		docURL = plcDirectory + "/" + did
+	case strings.HasPrefix(did, "did:web:"):
+		docURL = "https://" + strings.TrimPrefix(did, "did:web:") + "/.well-known/did.json"
+	default:
+		return nil, fmt.Errorf("unsupported DID method: %s", did)
+	}
+
+	req, err := http.NewRequestWithContext(requestContext, "GET", docURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", did, err)
+	}
+
+	var doc DIDDoc
+	if err := decodeJSON(body, &doc); err != nil {
+		return nil, err
+	}
+	if doc.ID != did {
+		return nil, fmt.Errorf("DID document is for %s, expected %s", doc.ID, did)
+	}
+	return &doc, nil
+}
+
+// Handle returns the handle the DID document claims, or "" if there is none
+func (d DIDDoc) Handle() string {
+	for _, aka := range d.AlsoKnownAs {
+		if handle, ok := strings.CutPrefix(aka, "at://"); ok {
+			return handle
+		}
+	}
+	return ""
+}
+
+// PDSEndpoint returns the URL of the PDS listed in the DID document, or "" if there is none
+func (d DIDDoc) PDSEndpoint() string {
+	for _, service := range d.Service {
+		if service.ID == "#atproto_pds" {
+			return service.ServiceEndpoint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// imageExtensions are the file extensions picked up from a directory of images
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+	".heic": true,
+	".heif": true,
+}
+
+// ImagesInDir lists the images in a directory, or those matching a glob
+// pattern like "photos/*.jpg", sorted by file name
+func ImagesInDir(pattern string) ([]string, error) {
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		pattern = filepath.Join(pattern, "*")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	var images []string
+	for _, match := range matches {
+		if !imageExtensions[strings.ToLower(filepath.Ext(match))] {
+			continue
+		}
+		if info, err := os.Stat(match); err != nil || info.IsDir() {
+			continue
+		}
+		images = append(images, match)
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images found in %s", pattern)
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return filepath.Base(images[i]) < filepath.Base(images[j])
+	})
+	return images, nil
+}
+
+// AltFromSidecar reads an image's alt text from the .txt file next to it, e.g.
+// "heron.txt" for "heron.jpg", returning "" if there is none
+func AltFromSidecar(imagePath string) (string, error) {
+	if IsImageURL(imagePath) {
+		return "", nil
+	}
+
+	sidecar := strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + ".txt"
+	data, err := os.ReadFile(sidecar)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read alt text from %s: %w", sidecar, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
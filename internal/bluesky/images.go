@@ -0,0 +1,556 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // Decoder for imageFormats
+	_ "image/jpeg" // Decoder for imageFormats
+	_ "image/png"  // Decoder for imageFormats
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alexisbcz/yabc/internal/blurhash"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+	"github.com/alexisbcz/yabc/internal/ui"
+	_ "golang.org/x/image/webp" // Decoder for imageFormats
+	"golang.org/x/sync/errgroup"
+)
+
+// MaxImages is the most images Bluesky allows in a single post
+const MaxImages = 4
+
+// maxImageSize is the largest image blob Bluesky accepts
+const maxImageSize = 1000000
+
+// maxParallelUploads bounds how many images are uploaded at once
+const maxParallelUploads = 4
+
+// imageFormats maps MIME types to the format names image decoders register
+// under. Each has its decoder imported above, so that wherever images are
+// prepared, their dimensions can be read.
+var imageFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// ImageAttachment is an image file to attach to a post
+type ImageAttachment struct {
+	Path string
+	Alt  string
+}
+
+// preparedImage is an image file inspected once for its type, size and
+// dimensions. It stays on disk and is streamed when uploaded, unless it had to
+// be converted or re-encoded, which leaves the new bytes in data. Its pixels
+// are only decoded when resizing or compressing needs them, and then once.
+type preparedImage struct {
+	// path is the image as given, a file or a URL
+	path string
+	// file is where the image is read from, a temporary download for a URL,
+	// or empty for a URL not downloaded in a dry run
+	file     string
+	alt      string
+	data     []byte
+	size     int64
+	mimeType string
+	width    int
+	height   int
+
+	// decoded holds the pixels once decoded, kept across steps that don't
+	// change them, like stripping metadata
+	decoded image.Image
+}
+
+// setData replaces the image with converted or re-encoded bytes
+func (img *preparedImage) setData(data []byte) {
+	img.data, img.size = data, int64(len(data))
+}
+
+// open returns a reader over the image, from memory or from the file
+func (img *preparedImage) open() (io.ReadCloser, error) {
+	if img.data != nil {
+		return io.NopCloser(bytes.NewReader(img.data)), nil
+	}
+	file, err := os.Open(img.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image file: %w", err)
+	}
+	return file, nil
+}
+
+// load returns the image's bytes, for the few steps that need all of them at once
+func (img *preparedImage) load() ([]byte, error) {
+	if img.data != nil {
+		return img.data, nil
+	}
+	data, err := os.ReadFile(img.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image file: %w", err)
+	}
+	return data, nil
+}
+
+// decode returns the image's pixels, decoding them on first use only. A JPEG
+// is turned upright as its EXIF orientation says, since re-encoding drops it.
+func (img *preparedImage) decode() (image.Image, error) {
+	if img.decoded != nil {
+		return img.decoded, nil
+	}
+	r, err := img.open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	decoded, format, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", img.path, err)
+	}
+	if format == "jpeg" {
+		exif, err := img.open()
+		if err != nil {
+			return nil, err
+		}
+		defer exif.Close()
+		decoded = orientImage(decoded, jpegOrientation(exif))
+	}
+	img.decoded = decoded
+	return decoded, nil
+}
+
+// contentHash identifies the image by its content, reading the file in chunks
+func (img *preparedImage) contentHash() (string, error) {
+	r, err := img.open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", fmt.Errorf("failed to read image file: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// buildImagesEmbed uploads the attachments and builds the app.bsky.embed.images embed
+func buildImagesEmbed(token *DIDResponse, attachments []ImageAttachment, dedupe bool, opts ImageOptions, dryRun bool) (map[string]interface{}, error) {
+	if len(attachments) > MaxImages {
+		return nil, fmt.Errorf("too many images: %d (%d maximum)", len(attachments), MaxImages)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Read and inspect each image once, then reuse it for the upload and aspect ratio
+	var images []*preparedImage
+	for _, attachment := range attachments {
+		// A dry run fetches nothing, so an image URL is only reported
+		if IsImageURL(attachment.Path) && dryRun {
+			images = append(images, &preparedImage{path: attachment.Path, alt: attachment.Alt})
+			continue
+		}
+
+		var img *preparedImage
+		var err error
+		if IsImageURL(attachment.Path) {
+			var tmp string
+			img, tmp, err = prepareRemoteImage(attachment.Path)
+			if err == nil {
+				defer tempfiles.Remove(tmp)
+			}
+		} else {
+			img, err = prepareImage(attachment.Path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload image: %w", err)
+		}
+		img.alt = attachment.Alt
+
+		if err := downscaleImage(img, opts); err != nil {
+			return nil, err
+		}
+
+		// Don't give away where a photo was taken, or with what
+		if opts.StripMetadata {
+			original, err := img.load()
+			if err != nil {
+				return nil, err
+			}
+			stripped, err := stripMetadata(original, img.mimeType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to strip metadata from %s (use --strip-metadata=false to upload it as is): %w", img.path, err)
+			}
+			if len(stripped) != len(original) {
+				slog.Debug("Stripped image metadata", "path", img.path, "bytesBefore", len(original), "bytesAfter", len(stripped))
+				img.setData(stripped)
+			}
+		}
+
+		// Bluesky has a 1MB limit, which photos straight off a phone often exceed
+		if img.size > maxImageSize && opts.Compress {
+			if img.mimeType == "image/gif" {
+				slog.Warn("Compressing GIF drops its animation", "path", img.path)
+			}
+			src, err := img.decode()
+			if err != nil {
+				return nil, err
+			}
+			data, width, height, err := compressDecoded(src, maxImageSize, opts.quality())
+			if err != nil {
+				return nil, fmt.Errorf("failed to compress %s: %w", img.path, err)
+			}
+			ui.Info("Compressed %s from %d to %d bytes", img.path, img.size, len(data))
+			img.setData(data)
+			img.mimeType, img.width, img.height = "image/jpeg", width, height
+		}
+		if img.size > maxImageSize {
+			return nil, fmt.Errorf("image file size too large: %s is %d bytes (1,000,000 bytes maximum, try --compress or --max-dimension)", img.path, img.size)
+		}
+
+		images = append(images, img)
+	}
+
+	images = checkDuplicateImages(images, dedupe)
+
+	var blobs []*UploadBlobResponse
+	if dryRun {
+		for _, img := range images {
+			blobs = append(blobs, dryRunBlob(img))
+		}
+	} else {
+		var err error
+		if blobs, err = uploadImages(token, images); err != nil {
+			return nil, fmt.Errorf("failed to upload image: %w", err)
+		}
+	}
+
+	var imageEmbeds []map[string]interface{}
+	for i, img := range images {
+		blobResp := blobs[i]
+
+		// Prepare the image embed. An empty alt is allowed, and better than a placeholder that describes nothing
+		imageEmbed := map[string]interface{}{
+			"alt": img.alt,
+			"image": map[string]interface{}{
+				"$type":    "blob",
+				"ref":      map[string]string{"$link": blobResp.Blob.Ref.Link},
+				"mimeType": blobResp.Blob.MimeType,
+				"size":     blobResp.Blob.Size,
+			},
+		}
+
+		// Add aspect ratio if we have dimensions
+		if img.width > 0 && img.height > 0 {
+			imageEmbed["aspectRatio"] = map[string]int{
+				"width":  img.width,
+				"height": img.height,
+			}
+		}
+
+		imageEmbeds = append(imageEmbeds, imageEmbed)
+	}
+
+	return map[string]interface{}{
+		"$type":  "app.bsky.embed.images",
+		"images": imageEmbeds,
+	}, nil
+}
+
+// checkDuplicateImages warns about attachments with identical content, and drops
+// the repeats when dedupe is set
+func checkDuplicateImages(images []*preparedImage, dedupe bool) []*preparedImage {
+	firstSeen := map[string]string{}
+	var unique []*preparedImage
+	for _, img := range images {
+		// An image URL isn't downloaded in a dry run, so there is nothing to compare
+		if img.file == "" && img.data == nil {
+			unique = append(unique, img)
+			continue
+		}
+		hash, err := img.contentHash()
+		if err != nil {
+			slog.Warn("Could not check for duplicate image", "path", img.path, "error", err)
+			unique = append(unique, img)
+			continue
+		}
+		if original, ok := firstSeen[hash]; ok {
+			if dedupe {
+				slog.Warn("Skipping duplicate image", "path", img.path, "duplicateOf", original)
+				continue
+			}
+			slog.Warn("Image is attached more than once", "path", img.path, "duplicateOf", original)
+			ui.Warn("%s is the same image as %s (use --dedupe-images to drop it)", img.path, original)
+		} else {
+			firstSeen[hash] = img.path
+		}
+		unique = append(unique, img)
+	}
+	return unique
+}
+
+// contentHash identifies a file by its content rather than its name
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// prepareImage inspects an image file, reading only as much of it as it needs:
+// the size comes from the file system, the type and dimensions from its header
+func prepareImage(imagePath string) (*preparedImage, error) {
+	// Check if file exists and is accessible
+	info, err := os.Stat(imagePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("image file does not exist: %s", imagePath)
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot access image file: %w", err)
+	}
+
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer file.Close()
+
+	// Determine MIME type, from the content when the extension doesn't tell
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read image file: %w", err)
+	}
+	mimeType, err := getMimeType(imagePath, header[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	img := &preparedImage{
+		path:     imagePath,
+		file:     imagePath,
+		size:     info.Size(),
+		mimeType: mimeType,
+	}
+
+	// Bluesky doesn't take HEIC, so upload iPhone photos as JPEG instead
+	content := header[:n]
+	var dimensions io.Reader = io.MultiReader(bytes.NewReader(content), file)
+	if mimeType == heicMimeType {
+		data, err := convertHEIC(imagePath)
+		if err != nil {
+			return nil, err
+		}
+		img.setData(data)
+		img.mimeType = "image/jpeg"
+		content = data
+		dimensions = bytes.NewReader(data)
+	}
+
+	// Get image dimensions for aspect ratio if possible, decoding only the header
+	img.width, img.height, err = getImageDimensions(dimensions)
+	if format, missing := missingDecoder(img.mimeType, content); missing && errors.Is(err, image.ErrFormat) {
+		// No decoder is registered for this format, which is a build issue rather than a bad file
+		slog.Warn("No decoder registered for image format", "path", imagePath, "format", format)
+		ui.Warn("%s support not compiled in, aspect ratio won't be specified for %s", format, imagePath)
+	} else if err != nil {
+		slog.Warn("Could not determine image dimensions", "path", imagePath, "error", err)
+		ui.Warn("Could not determine image dimensions, aspect ratio won't be specified")
+	}
+
+	return img, nil
+}
+
+// uploadImages uploads images at the same time, at most maxParallelUploads at
+// once, and returns their blob references in the same order. The first
+// failure cancels the uploads still running.
+func uploadImages(token *DIDResponse, images []*preparedImage) ([]*UploadBlobResponse, error) {
+	start := time.Now()
+	blobs := make([]*UploadBlobResponse, len(images))
+
+	g, ctx := errgroup.WithContext(requestContext)
+	g.SetLimit(maxParallelUploads)
+	for i, img := range images {
+		g.Go(func() error {
+			blobResp, err := uploadImage(ctx, token, img)
+			if err != nil {
+				return fmt.Errorf("%s: %w", img.path, err)
+			}
+			blobs[i] = blobResp
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Images uploaded", "count", len(images), "duration", time.Since(start))
+	return blobs, nil
+}
+
+// uploadImage uploads a prepared image to Bluesky and returns a blob reference
+func uploadImage(ctx context.Context, token *DIDResponse, img *preparedImage) (*UploadBlobResponse, error) {
+	slog.Info("Uploading image", "path", img.path, "size", img.size, "mimeType", img.mimeType)
+
+	// According to the Bluesky docs, we should send the raw image bytes directly, not as multipart
+	body, err := img.open()
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/com.atproto.repo.uploadBlob", API_URL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = img.size
+	// Reopening the image lets a rate-limited upload be sent again
+	req.GetBody = img.open
+
+	// Give slow uploads more time than other requests, in proportion to their size
+	req, cancel := withTimeout(req, uploadTimeout(int(img.size)))
+	defer cancel()
+
+	req.Header.Set("Content-Type", img.mimeType)
+
+	// Send the request
+	respBody, err := doAuthRequest(token, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the response
+	var blobResp UploadBlobResponse
+	if err := decodeJSON(respBody, &blobResp); err != nil {
+		return nil, err
+	}
+
+	if blobResp.Blob.Ref.Link == "" {
+		return nil, fmt.Errorf("invalid response: missing blob reference link - body: %s", string(respBody))
+	}
+
+	slog.Info("Image uploaded successfully", "blob_link", blobResp.Blob.Ref.Link, "size", img.size)
+	return &blobResp, nil
+}
+
+// getMimeType determines the MIME type of an image from its extension, or
+// from its first bytes when the extension is missing or unknown. Anything but
+// a supported image type is an error, so a blob is never mislabeled.
+func getMimeType(filename string, data []byte) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch ext {
+	case ".jpg", ".jpeg":
+		return "image/jpeg", nil
+	case ".png":
+		return "image/png", nil
+	case ".gif":
+		return "image/gif", nil
+	case ".webp":
+		return "image/webp", nil
+	case ".heic", ".heif":
+		return heicMimeType, nil
+	}
+
+	// DetectContentType doesn't know HEIC
+	if isHEIC(data) {
+		return heicMimeType, nil
+	}
+
+	// DetectContentType only looks at the first 512 bytes
+	mimeType := http.DetectContentType(data)
+	if _, ok := imageFormats[mimeType]; !ok {
+		return "", fmt.Errorf("unsupported image type for %s: %s (JPEG, PNG, GIF or WebP expected)", filename, mimeType)
+	}
+	slog.Debug("Detected image type from content", "path", filename, "mimeType", mimeType)
+	return mimeType, nil
+}
+
+// missingDecoder reports whether content really is in the format of mimeType,
+// going by its magic bytes, so that image.ErrFormat decoding it means no
+// decoder for that format was compiled in, rather than a bad or mislabeled file
+func missingDecoder(mimeType string, content []byte) (string, bool) {
+	format, ok := imageFormats[mimeType]
+	if !ok || http.DetectContentType(content) != mimeType {
+		return "", false
+	}
+	return format, true
+}
+
+// getImageDimensions determines the width and height of an image from its header
+func getImageDimensions(r io.Reader) (int, int, error) {
+	img, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode image dimensions: %w", err)
+	}
+
+	return img.Width, img.Height, nil
+}
+
+// ImageBlurhash decodes an image and computes its blurhash placeholder
+func ImageBlurhash(imagePath string) (string, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return blurhash.Encode(4, 3, img)
+}
+
+// cameraPrefixes are file name prefixes cameras and phones use for photos
+var cameraPrefixes = map[string]bool{
+	"IMG":   true,
+	"DSC":   true,
+	"DSCN":  true,
+	"DCIM":  true,
+	"PXL":   true,
+	"MVIMG": true,
+}
+
+// AltFromFilename derives alt text from an image's file name, e.g.
+// "sunset-over_the-bay.jpg" becomes "Sunset over the bay"
+func AltFromFilename(imagePath string) string {
+	// Only the path of an image URL names the file, not its query or host
+	if IsImageURL(imagePath) {
+		if u, err := url.Parse(imagePath); err == nil {
+			imagePath = u.Path
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(imagePath), filepath.Ext(imagePath))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+	}), " ")
+
+	// Camera names like "IMG_1234" or "PXL_20240101_123456" don't describe anything
+	descriptive := false
+	for _, word := range strings.Fields(name) {
+		if strings.ContainsFunc(word, unicode.IsLetter) && !cameraPrefixes[strings.ToUpper(word)] {
+			descriptive = true
+			break
+		}
+	}
+	if !descriptive {
+		return ""
+	}
+
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexisbcz/yabc/internal/ui"
+)
+
+// writeBenchJPEG writes a photo-sized JPEG with enough detail not to compress
+// to nothing, so resizing and compressing have real work to do
+func writeBenchJPEG(b *testing.B, dir, name string, seed int) string {
+	b.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 3000, 2000))
+	for y := 0; y < 2000; y++ {
+		for x := 0; x < 3000; x++ {
+			v := uint8((x*7 + y*13 + (x*y)%251 + seed) % 256)
+			img.Set(x, y, color.RGBA{R: v, G: uint8(x + seed), B: uint8(y), A: 255})
+		}
+	}
+
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+	if err := jpeg.Encode(file, img, &jpeg.Options{Quality: 95}); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// quiet silences logs and progress messages for the length of a test or benchmark
+func quiet(tb testing.TB) {
+	previous := slog.SetLogLoggerLevel(slog.LevelError)
+	ui.SetQuiet(true)
+	tb.Cleanup(func() {
+		slog.SetLogLoggerLevel(previous)
+		ui.SetQuiet(false)
+	})
+}
+
+func BenchmarkPrepareImage(b *testing.B) {
+	quiet(b)
+	path := writeBenchJPEG(b, b.TempDir(), "photo.jpg", 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := prepareImage(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildImagesEmbed(b *testing.B) {
+	quiet(b)
+
+	// The upload server reads each blob in full, as a PDS would, and returns a reference
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"blob":{"$type":"blob","ref":{"$link":"bafkreibench"},"mimeType":%q,"size":%d}}`, r.Header.Get("Content-Type"), n)
+	}))
+	b.Cleanup(server.Close)
+	previousAPI := API_URL
+	API_URL = server.URL + "/xrpc"
+	b.Cleanup(func() { API_URL = previousAPI })
+
+	dir := b.TempDir()
+	var attachments []ImageAttachment
+	for i := 0; i < MaxImages; i++ {
+		attachments = append(attachments, ImageAttachment{Path: writeBenchJPEG(b, dir, fmt.Sprintf("photo-%d.jpg", i), i), Alt: "A test pattern"})
+	}
+	token := &DIDResponse{DID: "did:plc:bench", AccessJwt: "bench"}
+	opts := ImageOptions{MaxDimension: 2000, Compress: true, StripMetadata: true}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := buildImagesEmbed(token, attachments, false, opts, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tinyWebP is a 1x1 lossless WebP, as the standard library has no WebP encoder
+const tinyWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+// encodeTestImage encodes a 3x2 image in the given format
+func encodeTestImage(t *testing.T, format string) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	case "webp":
+		var data []byte
+		data, err = base64.StdEncoding.DecodeString(tinyWebP)
+		buf.Write(data)
+	default:
+		t.Fatalf("unknown test format %s", format)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeTestFile writes data to name in a temporary directory
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMissingDecoder(t *testing.T) {
+	tests := []struct {
+		name       string
+		mimeType   string
+		content    []byte
+		wantFormat string
+		wantOK     bool
+	}{
+		{"png", "image/png", encodeTestImage(t, "png"), "png", true},
+		{"jpeg", "image/jpeg", encodeTestImage(t, "jpeg"), "jpeg", true},
+		{"gif", "image/gif", encodeTestImage(t, "gif"), "gif", true},
+		{"webp", "image/webp", encodeTestImage(t, "webp"), "webp", true},
+		{"mismatched extension", "image/webp", encodeTestImage(t, "png"), "", false},
+		{"not an image", "image/png", []byte("hello, world"), "", false},
+		{"unknown type", "image/bmp", []byte("BM\x00\x00"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := missingDecoder(tt.mimeType, tt.content)
+			if format != tt.wantFormat || ok != tt.wantOK {
+				t.Errorf("missingDecoder(%q) = %q, %v, want %q, %v", tt.mimeType, format, ok, tt.wantFormat, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPrepareImage(t *testing.T) {
+	quiet(t)
+	tests := []struct {
+		name     string
+		file     string
+		data     []byte
+		mimeType string
+		width    int
+		height   int
+	}{
+		{"png", "photo.png", encodeTestImage(t, "png"), "image/png", 3, 2},
+		{"jpeg", "photo.jpg", encodeTestImage(t, "jpeg"), "image/jpeg", 3, 2},
+		{"gif", "photo.gif", encodeTestImage(t, "gif"), "image/gif", 3, 2},
+		{"webp", "photo.webp", encodeTestImage(t, "webp"), "image/webp", 1, 1},
+		{"no extension", "photo", encodeTestImage(t, "png"), "image/png", 3, 2},
+		// The extension decides the type, but the dimensions still come from the content
+		{"mismatched extension", "photo.jpg", encodeTestImage(t, "png"), "image/jpeg", 3, 2},
+		// A file that isn't the image its extension says is still attached, without an aspect ratio
+		{"not an image", "photo.png", []byte("hello, world"), "image/png", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := prepareImage(writeTestFile(t, tt.file, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.mimeType != tt.mimeType || img.width != tt.width || img.height != tt.height {
+				t.Errorf("got %s %dx%d, want %s %dx%d", img.mimeType, img.width, img.height, tt.mimeType, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestPrepareImageUnknownType(t *testing.T) {
+	quiet(t)
+	_, err := prepareImage(writeTestFile(t, "notes", []byte("hello, world")))
+	if err == nil || !strings.Contains(err.Error(), "unsupported image type") {
+		t.Fatalf("got error %v, want an unsupported image type", err)
+	}
+}
+
+// rotatedJPEG is a 40x20 JPEG, red on its left half, whose EXIF orientation says
+// to display it turned a quarter clockwise, as phones save photos taken upright
+func rotatedJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.White)
+			if x < 20 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), orientationSegment(6)...), data[2:]...)
+}
+
+func TestDownscaleImageOrientation(t *testing.T) {
+	quiet(t)
+	img, err := prepareImage(writeTestFile(t, "photo.jpg", rotatedJPEG(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := downscaleImage(img, ImageOptions{MaxDimension: 20}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Upright, the photo is 20x40, so it fits in 20 as 10x20, with the red half on top
+	if img.width != 10 || img.height != 20 {
+		t.Fatalf("got %dx%d, want 10x20", img.width, img.height)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(img.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Bounds().Size(); got != image.Pt(10, 20) {
+		t.Fatalf("re-encoded JPEG is %v, want 10x20", got)
+	}
+	if r, _, b, _ := decoded.At(0, 0).RGBA(); r < 0xc000 || b > 0x4000 {
+		t.Errorf("top pixel isn't red, so the photo isn't upright")
+	}
+}
+
+func TestCompressImageOrientation(t *testing.T) {
+	quiet(t)
+	data, _, err := compressImage(rotatedJPEG(t), maxImageSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Bounds().Size(); got != image.Pt(20, 40) {
+		t.Fatalf("compressed JPEG is %v, want 20x40", got)
+	}
+}
+
+func TestOrientImage(t *testing.T) {
+	// A 3x2 image with a marked top-left corner, and where it ends up displayed
+	tests := []struct {
+		orientation uint16
+		width       int
+		height      int
+		corner      image.Point
+	}{
+		{0, 3, 2, image.Pt(0, 0)},
+		{1, 3, 2, image.Pt(0, 0)},
+		{2, 3, 2, image.Pt(2, 0)},
+		{3, 3, 2, image.Pt(2, 1)},
+		{4, 3, 2, image.Pt(0, 1)},
+		{5, 2, 3, image.Pt(0, 0)},
+		{6, 2, 3, image.Pt(1, 0)},
+		{7, 2, 3, image.Pt(1, 2)},
+		{8, 2, 3, image.Pt(0, 2)},
+	}
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+	for _, tt := range tests {
+		got := orientImage(src, tt.orientation)
+		if got.Bounds().Dx() != tt.width || got.Bounds().Dy() != tt.height {
+			t.Errorf("orientation %d: got %v, want %dx%d", tt.orientation, got.Bounds().Size(), tt.width, tt.height)
+			continue
+		}
+		if r, _, _, _ := got.At(tt.corner.X, tt.corner.Y).RGBA(); r == 0 {
+			t.Errorf("orientation %d: marked corner isn't at %v", tt.orientation, tt.corner)
+		}
+	}
+}
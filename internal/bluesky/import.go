@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// ImportedPost is a post read from another tool, one per line of a JSONL file
+type ImportedPost struct {
+	Text      string   `json:"text"`
+	CreatedAt string   `json:"createdAt,omitempty"`
+	Facets    []Facet  `json:"facets,omitempty"`
+	Langs     []string `json:"langs,omitempty"`
+	Blobs     []string `json:"blobs,omitempty"`
+}
+
+// ImportPost recreates an imported post, dropping facets that don't line up with its text
+func ImportPost(token *DIDResponse, post ImportedPost) (*PostCreateResponse, error) {
+	if post.Text == "" {
+		return nil, fmt.Errorf("post has no text")
+	}
+
+	// Media isn't part of the record, so it can't be carried over
+	if len(post.Blobs) > 0 {
+		slog.Warn("Post had media that must be re-uploaded manually", "blobs", post.Blobs)
+	}
+
+	record := newPostRecord(post.Text)
+	if post.CreatedAt != "" {
+		record["createdAt"] = post.CreatedAt
+	}
+
+	if facets := SanitizeFacets(post.Text, post.Facets); len(facets) > 0 {
+		record["facets"] = facets
+	}
+	if len(post.Langs) > 0 {
+		record["langs"] = post.Langs
+	}
+
+	return CreateRecord(token, "app.bsky.feed.post", record)
+}
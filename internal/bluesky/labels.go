@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SelfLabels are the content labels an author can put on their own post
+var SelfLabels = map[string]bool{
+	"sexual":        true,
+	"nudity":        true,
+	"porn":          true,
+	"graphic-media": true,
+}
+
+// ValidateSelfLabels checks each label is one of SelfLabels, dropping repeats
+func ValidateSelfLabels(labels []string) ([]string, error) {
+	seen := map[string]bool{}
+	var valid []string
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if !SelfLabels[label] {
+			return nil, fmt.Errorf("invalid label %q: expected one of %s", label, strings.Join(selfLabelNames(), ", "))
+		}
+		if !seen[label] {
+			seen[label] = true
+			valid = append(valid, label)
+		}
+	}
+	return valid, nil
+}
+
+// selfLabelNames lists SelfLabels in a stable order for messages
+func selfLabelNames() []string {
+	var names []string
+	for label := range SelfLabels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selfLabels builds the com.atproto.label.defs#selfLabels value of a record's labels field
+func selfLabels(labels []string) map[string]interface{} {
+	values := make([]map[string]string, 0, len(labels))
+	for _, label := range labels {
+		values = append(values, map[string]string{"val": label})
+	}
+	return map[string]interface{}{
+		"$type":  "com.atproto.label.defs#selfLabels",
+		"values": values,
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// maxLangs is the most languages a post record can list
+const maxLangs = 3
+
+// ValidateLangs checks that each language is a BCP-47 tag like "en" or "pt-BR"
+// and returns them in canonical form
+func ValidateLangs(langs []string) ([]string, error) {
+	if len(langs) > maxLangs {
+		return nil, fmt.Errorf("too many languages: %d (%d maximum)", len(langs), maxLangs)
+	}
+
+	var valid []string
+	for _, lang := range langs {
+		tag, err := language.Parse(strings.TrimSpace(lang))
+		if err != nil {
+			return nil, fmt.Errorf("invalid language %q: expected a BCP-47 code like en or pt-BR", lang)
+		}
+		valid = append(valid, tag.String())
+	}
+	return valid, nil
+}
+
+// DefaultLangs returns the language of the user's locale from $LANG, e.g. "fr"
+// for fr_FR.UTF-8, or nothing when it isn't set or is the C locale
+func DefaultLangs() []string {
+	locale := os.Getenv("LANG")
+
+	// Drop the encoding and modifier, as in fr_FR.UTF-8@euro
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return nil
+	}
+	// Feeds and filters match on the language alone, not the region
+	base, _ := tag.Base()
+	return []string{base.String()}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// ErrAlreadyLiked is returned along with the existing like when liking a post liked before
+var ErrAlreadyLiked = errors.New("post already liked")
+
+// LikePost likes a post, creating an app.bsky.feed.like record. The returned
+// URI is the like's, needed to unlike the post later. A post liked before
+// isn't liked twice: the existing like is returned with ErrAlreadyLiked.
+func LikePost(token *DIDResponse, uri string) (*PostCreateResponse, error) {
+	subject, err := ResolvePostRef(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// The app view knows whether we already liked the post
+	if posts, err := GetPosts(token, []string{subject.URI}); err != nil {
+		slog.Warn("Could not check whether the post is already liked", "uri", subject.URI, "error", err)
+	} else if len(posts) == 1 && posts[0].Viewer != nil && posts[0].Viewer.Like != "" {
+		return &PostCreateResponse{URI: posts[0].Viewer.Like}, ErrAlreadyLiked
+	}
+
+	return CreateRecord(token, "app.bsky.feed.like", map[string]interface{}{
+		"$type":     "app.bsky.feed.like",
+		"subject":   subject,
+		"createdAt": getCurrentTime(),
+	})
+}
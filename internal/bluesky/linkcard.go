@@ -0,0 +1,197 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/ui"
+)
+
+const (
+	// maxPageSize is how much of a page is read looking for its metadata, which lives in <head>
+	maxPageSize = 1 << 20
+
+	// maxThumbSize is the largest thumbnail Bluesky accepts
+	maxThumbSize = 1000000
+
+	// maxThumbFetch is the largest page image fetched, to be compressed down to maxThumbSize
+	maxThumbFetch = 10 << 20
+)
+
+var (
+	metaTag   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attribute = regexp.MustCompile(`(?is)([a-z][a-z0-9:_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	titleTag  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// ExternalEmbed is a link card: a page's URL, title, description and thumbnail image
+type ExternalEmbed struct {
+	URI         string
+	Title       string
+	Description string
+	// ThumbURL is the page's og:image, fetched and uploaded only when posting with a thumbnail
+	ThumbURL string
+}
+
+// fetchLinkCard fetches a page and reads its OpenGraph title, description and
+// image, falling back to <title> and then the URL itself for pages without them
+func fetchLinkCard(pageURL string) (*ExternalEmbed, error) {
+	card, _, err := fetchPageMeta(pageURL)
+	return card, err
+}
+
+// fetchPageMeta fetches a page for its link card, also returning all of its
+// <meta> tags by property or name, lowercased
+func fetchPageMeta(pageURL string) (*ExternalEmbed, map[string]string, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, nil, fmt.Errorf("invalid link card URL %q", pageURL)
+	}
+
+	body, err := fetchURL(pageURL, maxPageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	page := string(body)
+
+	meta := map[string]string{}
+	for _, tag := range metaTag.FindAllString(page, -1) {
+		attrs := map[string]string{}
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3]
+		}
+		key := attrs["property"]
+		if key == "" {
+			key = attrs["name"]
+		}
+		if key = strings.ToLower(key); key != "" && meta[key] == "" {
+			meta[key] = strings.TrimSpace(html.UnescapeString(attrs["content"]))
+		}
+	}
+
+	card := &ExternalEmbed{
+		URI:         pageURL,
+		Title:       firstNonEmpty(meta["og:title"], meta["twitter:title"]),
+		Description: firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]),
+	}
+	if card.Title == "" {
+		if m := titleTag.FindStringSubmatch(page); m != nil {
+			card.Title = strings.TrimSpace(html.UnescapeString(m[1]))
+		}
+	}
+	if card.Title == "" {
+		card.Title = pageURL
+	}
+
+	// Images are often given relative to the page
+	if image := firstNonEmpty(meta["og:image"], meta["twitter:image"]); image != "" {
+		if ref, err := base.Parse(image); err == nil {
+			card.ThumbURL = ref.String()
+		}
+	}
+
+	return card, meta, nil
+}
+
+// buildExternalEmbed builds the app.bsky.embed.external embed for a link card,
+// uploading its thumbnail unless withThumb is false. The thumbnail is optional,
+// so a missing or unusable image only costs a warning.
+func buildExternalEmbed(token *DIDResponse, card *ExternalEmbed, withThumb bool) map[string]interface{} {
+	external := map[string]interface{}{
+		"uri":         card.URI,
+		"title":       card.Title,
+		"description": card.Description,
+	}
+
+	if withThumb && card.ThumbURL != "" {
+		thumb, err := uploadThumb(token, card.ThumbURL)
+		if err != nil {
+			slog.Warn("Posting link card without a thumbnail", "image", card.ThumbURL, "error", err)
+			ui.Warn("Could not use the page's image, posting the link card without a thumbnail")
+		} else {
+			external["thumb"] = thumb
+		}
+	}
+
+	return map[string]interface{}{
+		"$type":    "app.bsky.embed.external",
+		"external": external,
+	}
+}
+
+// uploadThumb fetches a thumbnail image and uploads it as a blob
+func uploadThumb(token *DIDResponse, imageURL string) (*BlobReference, error) {
+	data, err := fetchURL(imageURL, maxThumbFetch+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxThumbFetch {
+		return nil, fmt.Errorf("thumbnail too large (over %d bytes)", maxThumbFetch)
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("thumbnail is %s, not an image", mimeType)
+	}
+
+	// Sites often use large images for cards, so shrink them rather than going
+	// without. A GIF's first frame makes a still thumbnail, e.g. for Tenor GIFs.
+	if len(data) > maxThumbSize || mimeType == "image/gif" {
+		slog.Info("Compressing link card thumbnail", "image", imageURL, "size", len(data))
+		if data, mimeType, err = compressImage(data, maxThumbSize); err != nil {
+			return nil, err
+		}
+	}
+
+	thumb := &preparedImage{path: imageURL, mimeType: mimeType}
+	thumb.setData(data)
+	blobResp, err := uploadImage(requestContext, token, thumb)
+	if err != nil {
+		return nil, err
+	}
+	return &BlobReference{Type: "blob", Ref: blobResp.Blob.Ref, MimeType: blobResp.Blob.MimeType, Size: blobResp.Blob.Size}, nil
+}
+
+// fetchURL GETs a web page or image, reading at most limit bytes of it
+func fetchURL(rawURL string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(requestContext, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "yabc (+https://github.com/alexisbcz/yabc)")
+
+	req, cancel := withTimeout(req, requestTimeout)
+	defer cancel()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrNotOwnPost is returned when moving or deleting a post from someone else's repo
+var ErrNotOwnPost = errors.New("not your post")
+
+// ErrMoveUnderItself is returned when moving a post under itself or one of its own replies
+var ErrMoveUnderItself = errors.New("can't move a post under itself or its replies")
+
+// ErrOriginalNotDeleted is returned when a moved post was recreated but the
+// original couldn't be deleted, leaving both in place
+var ErrOriginalNotDeleted = errors.New("post recreated, but the original could not be deleted")
+
+// maxThreadDepth bounds the walk up a thread in hasAncestor, in case a record
+// points back at one of its own replies
+const maxThreadDepth = 1000
+
+// hasAncestor reports whether the post at uri is a reply, however deep, to
+// ancestor, by following each post's parent up the thread. A deleted post
+// breaks the chain, so what was above it isn't reached.
+func hasAncestor(uri, ancestor string) (bool, error) {
+	for i := 0; i < maxThreadDepth; i++ {
+		parsed, err := ParseATURI(uri)
+		if err != nil {
+			return false, err
+		}
+		record, err := GetRecord(parsed.Repo, parsed.Collection, parsed.RKey)
+		if isNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch %s: %w", uri, err)
+		}
+
+		var post PostRecord
+		if err := json.Unmarshal(record.Value, &post); err != nil {
+			return false, fmt.Errorf("failed to decode %s: %w", uri, err)
+		}
+		if post.Reply == nil {
+			return false, nil
+		}
+		if post.Reply.Parent.URI == ancestor {
+			return true, nil
+		}
+		uri = post.Reply.Parent.URI
+	}
+	return false, fmt.Errorf("thread above %s is more than %d posts deep", uri, maxThreadDepth)
+}
+
+// MovePost turns a post into a reply to parentURI. A post's reply field can't
+// be edited, so the post is recreated as a reply and the original deleted,
+// which loses its likes, reposts and replies. The original is only deleted
+// once the new post exists.
+func MovePost(token *DIDResponse, uri, parentURI string) (*PostCreateResponse, error) {
+	parsed, err := ParseOwnPostURI(token, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := GetRecord(parsed.Repo, parsed.Collection, parsed.RKey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrPostNotFound, uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+
+	// Keep the record as is, text, facets and embed included: its blobs are
+	// still referenced by the new post, so they survive the original's deletion
+	var record map[string]interface{}
+	if err := json.Unmarshal(original.Value, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", uri, err)
+	}
+	var originalPost PostRecord
+	if err := json.Unmarshal(original.Value, &originalPost); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", uri, err)
+	}
+
+	reply, err := ResolveReplyRef(parentURI)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Parent.URI == original.URI || reply.Root.URI == original.URI {
+		return nil, ErrMoveUnderItself
+	}
+	// A reply in the middle of a thread shares its root with its own replies,
+	// so only the parent chain tells whether the new parent is one of them
+	if originalPost.Reply != nil && reply.Root.URI == originalPost.Reply.Root.URI {
+		under, err := hasAncestor(reply.Parent.URI, original.URI)
+		if err != nil {
+			return nil, err
+		}
+		if under {
+			return nil, ErrMoveUnderItself
+		}
+	}
+	record["reply"] = reply
+	record["createdAt"] = getCurrentTime()
+
+	moved, err := CreateRecord(token, "app.bsky.feed.post", record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recreate post as a reply: %w", err)
+	}
+	slog.Info("Post recreated as a reply", "uri", moved.URI, "parent", reply.Parent.URI)
+
+	if err := DeleteRecord(token, parsed.Collection, parsed.RKey); err != nil {
+		return moved, fmt.Errorf("%w: %w", ErrOriginalNotDeleted, err)
+	}
+	slog.Info("Original post deleted", "uri", original.URI)
+
+	return moved, nil
+}
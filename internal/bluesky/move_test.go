@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMovePostUnderItself(t *testing.T) {
+	quiet(t)
+
+	// A thread with the post to move in the middle of it:
+	// root <- post <- child <- grandchild, and root <- other
+	parents := map[string]string{
+		"root":       "",
+		"post":       "root",
+		"child":      "post",
+		"grandchild": "child",
+		"other":      "root",
+	}
+	postURI := func(rkey string) string { return "at://did:plc:alice/app.bsky.feed.post/" + rkey }
+
+	var created atomic.Int32
+	testPDS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/com.atproto.repo.getRecord"):
+			rkey := r.URL.Query().Get("rkey")
+			parent, ok := parents[rkey]
+			if !ok {
+				notFound(w, false)
+				return
+			}
+			value := `{"$type":"app.bsky.feed.post","text":"hello","createdAt":"2025-01-02T03:04:05.000Z"}`
+			if parent != "" {
+				value = fmt.Sprintf(`{"$type":"app.bsky.feed.post","text":"hello","createdAt":"2025-01-02T03:04:05.000Z","reply":{"root":{"uri":%q,"cid":"bafyroot"},"parent":{"uri":%q,"cid":"bafy%s"}}}`,
+					postURI("root"), postURI(parent), parent)
+			}
+			fmt.Fprintf(w, `{"uri":%q,"cid":"bafy%s","value":%s}`, postURI(rkey), rkey, value)
+		case strings.HasSuffix(r.URL.Path, "/com.atproto.repo.createRecord"):
+			created.Add(1)
+			fmt.Fprintf(w, `{"uri":%q,"cid":"bafymoved"}`, postURI("moved"))
+		case strings.HasSuffix(r.URL.Path, "/com.atproto.repo.deleteRecord"):
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	token := &DIDResponse{DID: "did:plc:alice", AccessJwt: "token"}
+	tests := []struct {
+		parent string
+		want   error
+	}{
+		{"post", ErrMoveUnderItself},
+		{"child", ErrMoveUnderItself},
+		{"grandchild", ErrMoveUnderItself},
+		{"other", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.parent, func(t *testing.T) {
+			before := created.Load()
+			_, err := MovePost(token, postURI("post"), postURI(tt.parent))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if posted := created.Load() > before; posted != (tt.want == nil) {
+				t.Errorf("post recreated: %v, want %v", posted, tt.want == nil)
+			}
+		})
+	}
+}
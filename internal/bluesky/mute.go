@@ -0,0 +1,39 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMuteSelf is returned when muting or unmuting your own account
+var ErrMuteSelf = errors.New("you can't mute yourself")
+
+// Mute mutes an account, given by handle or DID. Unlike a block, a mute isn't
+// a record in the user's repo: the app view keeps it privately, and nobody
+// else can see it. Muting an account already muted does nothing.
+func Mute(token *DIDResponse, actor string) (string, error) {
+	return setMuted(token, "app.bsky.graph.muteActor", actor)
+}
+
+// Unmute unmutes an account, given by handle or DID. Unmuting an account that
+// isn't muted does nothing.
+func Unmute(token *DIDResponse, actor string) (string, error) {
+	return setMuted(token, "app.bsky.graph.unmuteActor", actor)
+}
+
+// setMuted calls muteActor or unmuteActor, returning the DID of the account
+func setMuted(token *DIDResponse, method, actor string) (string, error) {
+	did, err := resolveActorDID(actor)
+	if err != nil {
+		return "", err
+	}
+	if did == token.DID {
+		return "", ErrMuteSelf
+	}
+
+	if err := xrpcPost(token, method, map[string]string{"actor": did}, nil); err != nil {
+		return "", fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	return did, nil
+}
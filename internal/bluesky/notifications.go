@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Notification is a single like, repost, follow, mention, reply or quote
+type Notification struct {
+	URI           string           `json:"uri"`
+	CID           string           `json:"cid"`
+	Author        ProfileViewBasic `json:"author"`
+	Reason        string           `json:"reason"`
+	ReasonSubject string           `json:"reasonSubject,omitempty"`
+	Record        PostRecord       `json:"record"`
+	IsRead        bool             `json:"isRead"`
+	IndexedAt     string           `json:"indexedAt"`
+}
+
+// NotificationsResponse is a page of notifications
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Cursor        string         `json:"cursor,omitempty"`
+	SeenAt        string         `json:"seenAt,omitempty"`
+}
+
+// ListNotifications fetches a page of the authenticated user's notifications
+func ListNotifications(token *DIDResponse, cursor string, limit int) (*NotificationsResponse, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var notifications NotificationsResponse
+	if err := xrpcGet(token, "app.bsky.notification.listNotifications", query, &notifications); err != nil {
+		return nil, err
+	}
+
+	return &notifications, nil
+}
+
+// UpdateSeen marks every notification up to seenAt as read
+func UpdateSeen(token *DIDResponse, seenAt time.Time) error {
+	requestBody := map[string]string{
+		"seenAt": seenAt.UTC().Format(time.RFC3339Nano),
+	}
+	return xrpcPost(token, "app.bsky.notification.updateSeen", requestBody, nil)
+}
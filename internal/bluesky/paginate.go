@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// MaxFetchAll caps how many items FetchAll collects, so a huge list can't run away
+	MaxFetchAll = 10000
+
+	// LargeFetch is the item count above which fetching everything deserves a confirmation
+	LargeFetch = 1000
+
+	// pageSize is the page size used when fetching everything, the most most methods allow
+	pageSize = 100
+
+	// pagePacing is the pause between pages, to stay well clear of rate limits
+	pagePacing = 250 * time.Millisecond
+)
+
+// FetchAll follows cursors from fetch until the list ends or MaxFetchAll
+// items were collected, reporting whether it stopped at the cap
+func FetchAll[T any](fetch func(cursor string, limit int) ([]T, string, error)) ([]T, bool, error) {
+	var items []T
+	cursor := ""
+	for {
+		page, next, err := fetch(cursor, pageSize)
+		if err != nil {
+			return items, false, err
+		}
+		items = append(items, page...)
+		slog.Debug("Fetched page", "items", len(page), "total", len(items))
+
+		if next == "" || len(page) == 0 {
+			return items, false, nil
+		}
+		if len(items) >= MaxFetchAll {
+			return items[:MaxFetchAll], true, nil
+		}
+
+		cursor = next
+		time.Sleep(pagePacing)
+	}
+}
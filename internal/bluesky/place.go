@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Place is an opt-in location annotation for a post. Bluesky has no location
+// field, so this is non-standard: the place is shown as a line of text and a
+// tag, and coordinates go in a custom "place" field for geo-aware feeds.
+type Place struct {
+	Name      string `json:"name"`
+	Latitude  string `json:"latitude,omitempty"`
+	Longitude string `json:"longitude,omitempty"`
+}
+
+// ParsePlace validates and normalizes a place name like "paris ,  France" and
+// optional "lat,lon" coordinates
+func ParsePlace(name, coords string) (*Place, error) {
+	var parts []string
+	for _, part := range strings.Split(name, ",") {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("place name is empty")
+	}
+
+	place := &Place{Name: strings.Join(parts, ", ")}
+	if coords == "" {
+		return place, nil
+	}
+
+	lat, lon, ok := strings.Cut(coords, ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid coordinates %q: expected latitude,longitude", coords)
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil, fmt.Errorf("invalid latitude %q: must be between -90 and 90", lat)
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil, fmt.Errorf("invalid longitude %q: must be between -180 and 180", lon)
+	}
+
+	// The atproto data model has no floats, so coordinates are stored as strings
+	place.Latitude = strconv.FormatFloat(latitude, 'f', 6, 64)
+	place.Longitude = strconv.FormatFloat(longitude, 'f', 6, 64)
+
+	return place, nil
+}
+
+// applyPlace adds the place line, tag and coordinates to a post record
+func applyPlace(record map[string]interface{}, place *Place) {
+	record["text"] = fmt.Sprintf("%s\n\n📍 %s", record["text"], place.Name)
+	if place.Latitude != "" {
+		record["place"] = place
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import "fmt"
+
+// maxPollOptions keeps the self-thread of options short enough to fetch in one go
+const maxPollOptions = 10
+
+// Poll is a question post followed by a self-thread with one reply per option
+type Poll struct {
+	Question *PostCreateResponse
+	Options  []*PostCreateResponse
+}
+
+// PollOption is the tally for a single poll option
+type PollOption struct {
+	URI   string
+	Text  string
+	Likes int
+}
+
+// CreatePoll posts a question, then replies to it with each option in order so
+// people can vote by liking the option they choose
+func CreatePoll(token *DIDResponse, question string, options []string) (*Poll, error) {
+	if len(options) < 2 {
+		return nil, fmt.Errorf("a poll needs at least 2 options")
+	}
+	if len(options) > maxPollOptions {
+		return nil, fmt.Errorf("a poll can have at most %d options", maxPollOptions)
+	}
+
+	// Build every post first, so an option too long fails before anything is posted
+	questionText := question + "\n\nLike a reply below to vote."
+	questionRecord, err := buildPostRecord(token, questionText, PostOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("question: %w", err)
+	}
+	optionTexts := make([]string, len(options))
+	optionRecords := make([]map[string]interface{}, len(options))
+	for i, option := range options {
+		optionTexts[i] = fmt.Sprintf("%d. %s", i+1, option)
+		if optionRecords[i], err = buildPostRecord(token, optionTexts[i], PostOptions{}); err != nil {
+			return nil, fmt.Errorf("option %d: %w", i+1, err)
+		}
+	}
+
+	questionResp, err := createPostRecord(token, questionText, questionRecord)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post question: %w", err)
+	}
+
+	poll := &Poll{Question: questionResp}
+	parent := questionResp.Ref()
+	for i, record := range optionRecords {
+		reply, err := NewReplyRef(questionResp.Ref(), parent)
+		if err != nil {
+			return poll, err
+		}
+		record["reply"] = reply
+
+		optionResp, err := createPostRecord(token, optionTexts[i], record)
+		if err != nil {
+			return poll, fmt.Errorf("failed to post option %d: %w", i+1, err)
+		}
+
+		poll.Options = append(poll.Options, optionResp)
+		parent = optionResp.Ref()
+	}
+
+	return poll, nil
+}
+
+// GetPollResults finds the options of a poll created by CreatePoll and tallies the likes on each
+func GetPollResults(token *DIDResponse, questionURI string) ([]PollOption, error) {
+	thread, err := GetPostThread(token, questionURI, maxPollOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch poll thread: %w", err)
+	}
+	if !thread.Thread.IsPost() {
+		return nil, fmt.Errorf("poll question not found or deleted")
+	}
+
+	// Options form a self-thread: follow the author's own reply at each level
+	author := thread.Thread.Post.Author.DID
+	var options []PollOption
+	node := &thread.Thread
+	for {
+		var next *ThreadViewPost
+		for i := range node.Replies {
+			reply := &node.Replies[i]
+			if reply.IsPost() && reply.Post.Author.DID == author {
+				next = reply
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		likes, err := CountLikes(token, next.Post.URI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count likes for %s: %w", next.Post.URI, err)
+		}
+
+		options = append(options, PollOption{
+			URI:   next.Post.URI,
+			Text:  next.Post.Record.Text,
+			Likes: likes,
+		})
+		node = next
+	}
+
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no poll options found under %s", questionURI)
+	}
+
+	return options, nil
+}
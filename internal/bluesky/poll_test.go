@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreatePollTooLong(t *testing.T) {
+	quiet(t)
+	var requests atomic.Int32
+	testPDS(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	})
+
+	token := &DIDResponse{DID: "did:plc:alice", AccessJwt: "token"}
+	long := strings.Repeat("a", 300)
+	tests := []struct {
+		name     string
+		question string
+		options  []string
+		want     string
+	}{
+		// The vote instructions appended to the question count towards its length
+		{"question", strings.Repeat("a", 280), []string{"yes", "no"}, "question: post is"},
+		{"last option", "Tabs or spaces?", []string{"tabs", "spaces", long}, "option 3: post is"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll, err := CreatePoll(token, tt.question, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+			if poll != nil {
+				t.Errorf("got a poll, want nothing posted")
+			}
+		})
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("PDS got %d requests, want none", n)
+	}
+}
@@ -2,235 +2,251 @@
 package bluesky
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"image"
-	"io"
 	"log/slog"
-	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 	"time"
-)
-
-// CreatePost sends a request to create a new post on Bluesky
-func CreatePost(token *DIDResponse, content string, imagePath string) error {
-	// Prepare the post record
-	record := map[string]interface{}{
-		"$type":     "app.bsky.feed.post",
-		"text":      content,
-		"createdAt": getCurrentTime(),
-	}
-
-	// Add image attachment if provided
-	if imagePath != "" {
-		fmt.Println("Uploading image:", imagePath)
-
-		blobResp, err := uploadImage(token, imagePath)
-		if err != nil {
-			return fmt.Errorf("failed to upload image: %w", err)
-		}
-
-		// Get image dimensions for aspect ratio if possible
-		width, height, err := getImageDimensions(imagePath)
-		if err != nil {
-			slog.Warn("Could not determine image dimensions", "error", err)
-			fmt.Println("Warning: Could not determine image dimensions, aspect ratio won't be specified")
-		}
 
-		// Prepare the image embed
-		imageEmbed := map[string]interface{}{
-			"alt": "Attached image", // Default alt text
-			"image": map[string]interface{}{
-				"$type":    "blob",
-				"ref":      map[string]string{"$link": blobResp.Blob.Ref.Link},
-				"mimeType": blobResp.Blob.MimeType,
-				"size":     blobResp.Blob.Size,
-			},
-		}
+	"github.com/alexisbcz/yabc/internal/richtext"
+)
 
-		// Add aspect ratio if we have dimensions
-		if width > 0 && height > 0 {
-			imageEmbed["aspectRatio"] = map[string]int{
-				"width":  width,
-				"height": height,
-			}
-		}
+// maxTags is the most outline tags a post record can carry
+const maxTags = 8
+
+// maxClockSkew is how far in the future a backdated createdAt may still be,
+// to allow for a clock that runs a little fast
+const maxClockSkew = 5 * time.Minute
+
+// PostOptions holds everything besides the text that goes into a new post
+type PostOptions struct {
+	Images []ImageAttachment
+	Video  *VideoAttachment
+	// GIF is a Tenor GIF, posted as a link card Bluesky plays as an animation
+	GIF *GIFAttachment
+	// Card is a URL to show as a link card
+	Card string
+	// CardNoThumb skips fetching and uploading the link card's image
+	CardNoThumb bool
+	// Quote is the post this one quotes, resolved with ResolvePostRef
+	Quote *StrongRef
+	Reply *ReplyRef
+	// Tags are extra hashtags stored on the record without appearing in the text
+	Tags   []string
+	Place  *Place
+	Facets []Facet
+	// Langs are the BCP-47 codes of the languages the post is written in
+	Langs []string
+	// NoLinkify leaves bare URLs in the text as plain text
+	NoLinkify bool
+	// Labels are content warnings from SelfLabels, e.g. "graphic-media"
+	Labels []string
+	// CreatedAt backdates the post, e.g. when importing old content; zero means now
+	CreatedAt time.Time
+	// DedupeImages drops repeated images instead of only warning about them
+	DedupeImages bool
+	// ImageOptions controls resizing and re-encoding of images before upload
+	ImageOptions ImageOptions
+	// MaxGraphemes is the longest text allowed, 0 for richtext.MaxLength
+	MaxGraphemes int
+	// DryRun builds the record without uploading or fetching anything, see DryRunThread
+	DryRun bool
+}
 
-		// Add the image to the post record
-		record["embed"] = map[string]interface{}{
-			"$type":  "app.bsky.embed.images",
-			"images": []map[string]interface{}{imageEmbed},
-		}
+// maxGraphemes returns the length limit for the post's text
+func (o PostOptions) maxGraphemes() int {
+	if o.MaxGraphemes > 0 {
+		return o.MaxGraphemes
 	}
+	return richtext.MaxLength
+}
 
-	// Create the request body
-	requestBody := map[string]interface{}{
-		"collection": "app.bsky.feed.post",
-		"repo":       token.DID,
-		"record":     record,
-	}
+// PostLength counts a post's text in graphemes, which is what Bluesky's length
+// limit applies to: an emoji joined from several, like 👨‍👩‍👧, counts once
+func PostLength(text string) int {
+	return richtext.Length(text)
+}
 
-	jsonBody, err := json.Marshal(requestBody)
+// CreatePost sends a request to create a new post on Bluesky
+func CreatePost(token *DIDResponse, content string, opts PostOptions) (*PostCreateResponse, error) {
+	record, err := buildPostRecord(token, content, opts)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, err
 	}
+	return createPostRecord(token, content, record)
+}
 
+// createPostRecord creates a post from the record buildPostRecord built for content
+func createPostRecord(token *DIDResponse, content string, record map[string]interface{}) (*PostCreateResponse, error) {
 	// Send the request
-	url := fmt.Sprintf("%s/com.atproto.repo.createRecord", API_URL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	postResp, err := CreateRecord(token, "app.bsky.feed.post", record)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessJwt))
-	req.Header.Set("Content-Type", "application/json")
+	// createRecord only returns the URI and CID, so fill in what was posted
+	postResp.Record.Type = "app.bsky.feed.post"
+	postResp.Record.Text = content
+	postResp.Record.CreatedAt, _ = record["createdAt"].(string)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+	slog.Info("Post created", "uri", postResp.URI, "cid", postResp.CID)
+	return postResp, nil
+}
+
+// buildPostRecord builds the app.bsky.feed.post record for a post, uploading
+// its media along the way unless opts.DryRun is set
+func buildPostRecord(token *DIDResponse, content string, opts PostOptions) (map[string]interface{}, error) {
+	if length := PostLength(content); length > opts.maxGraphemes() {
+		return nil, fmt.Errorf("post is %d graphemes, maximum is %d", length, opts.maxGraphemes())
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var errResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
-			slog.Error("API error response", "response", errResp)
+	// Prepare the post record
+	record := newPostRecord(content)
+	if !opts.CreatedAt.IsZero() {
+		if err := ValidateCreatedAt(opts.CreatedAt); err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		record["createdAt"] = formatTimestamp(opts.CreatedAt)
 	}
 
-	// Decode the response to get the post details
-	var postResp PostCreateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&postResp); err == nil {
-		slog.Info("Post created", "uri", postResp.URI, "cid", postResp.CID)
+	// Attach rich-text annotations, including links, hashtags and mentions found in the text, dropping any that don't fit the text
+	resolve := ResolveHandle
+	if opts.DryRun {
+		resolve = dryRunResolveHandle
 	}
-
-	return nil
-}
-
-// getCurrentTime returns the current time in the format required by Bluesky
-func getCurrentTime() string {
-	// Bluesky prefers the "Z" format over "+00:00"
-	return time.Now().UTC().Format(time.RFC3339Nano)[:23] + "Z"
-}
-
-// uploadImage uploads an image to Bluesky and returns a blob reference
-func uploadImage(token *DIDResponse, imagePath string) (*UploadBlobResponse, error) {
-	// Check if file exists and is accessible
-	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("image file does not exist: %s", imagePath)
-	} else if err != nil {
-		return nil, fmt.Errorf("cannot access image file: %w", err)
+	if facets := SanitizeFacets(content, mergeFacets(opts.Facets, buildFacets(content, resolve, !opts.NoLinkify))); len(facets) > 0 {
+		record["facets"] = facets
 	}
 
-	// Read the file content instead of keeping it open
-	imgData, err := os.ReadFile(imagePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read image file: %w", err)
+	// Say what language the post is in, for filtering and translation
+	if len(opts.Langs) > 0 {
+		langs, err := ValidateLangs(opts.Langs)
+		if err != nil {
+			return nil, err
+		}
+		record["langs"] = langs
 	}
 
-	// Check file size - Bluesky has a 1MB limit
-	if len(imgData) > 1000000 {
-		return nil, fmt.Errorf("image file size too large: %d bytes (1,000,000 bytes maximum)", len(imgData))
+	// Warn about adult or graphic content, so it is hidden or blurred as the reader chose
+	if len(opts.Labels) > 0 {
+		labels, err := ValidateSelfLabels(opts.Labels)
+		if err != nil {
+			return nil, err
+		}
+		record["labels"] = selfLabels(labels)
 	}
 
-	// Determine MIME type
-	mimeType := getMimeType(imagePath)
-	if mimeType == "" {
-		// Default to a common image type if we can't determine
-		mimeType = "image/jpeg"
+	// Place the post in a thread if it is a reply
+	if opts.Reply != nil {
+		record["reply"] = opts.Reply
 	}
 
-	slog.Info("Uploading image", "path", imagePath, "size", len(imgData), "mimeType", mimeType)
-
-	// According to the Bluesky docs, we should send the raw image bytes directly, not as multipart
-	url := fmt.Sprintf("%s/com.atproto.repo.uploadBlob", API_URL)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(imgData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// Annotate the post with a location, which also becomes a tag
+	tags := opts.Tags
+	if opts.Place != nil {
+		applyPlace(record, opts.Place)
+		tags = append(tags, opts.Place.Name)
+	}
+	if len(tags) > 0 {
+		if len(tags) > maxTags {
+			return nil, fmt.Errorf("too many tags: %d (%d maximum)", len(tags), maxTags)
+		}
+		record["tags"] = tags
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessJwt))
-	req.Header.Set("Content-Type", mimeType)
-
-	// Send the request
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	// A post has at most one embed
+	embeds := 0
+	for _, set := range []bool{len(opts.Images) > 0, opts.Video != nil, opts.GIF != nil, opts.Card != "", opts.Quote != nil} {
+		if set {
+			embeds++
+		}
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	if embeds > 1 {
+		return nil, fmt.Errorf("a post can have images, a video, a GIF, a link card or a quote, but only one of them")
 	}
-	defer resp.Body.Close()
 
-	// Read the response body for better error messages
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	// Embed the quoted post
+	if opts.Quote != nil {
+		record["embed"] = quoteEmbed(*opts.Quote)
+	}
+
+	// Add a link card if requested
+	if opts.Card != "" && opts.DryRun {
+		record["embed"] = dryRunExternalEmbed(opts.Card)
+	} else if opts.Card != "" {
+		card, err := fetchLinkCard(opts.Card)
+		if err != nil {
+			return nil, err
+		}
+		record["embed"] = buildExternalEmbed(token, card, !opts.CardNoThumb)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		// Try to parse error response
-		var errResp map[string]interface{}
-		if err := json.Unmarshal(respBody, &errResp); err == nil {
-			slog.Error("API error response", "response", errResp)
-			if message, ok := errResp["message"].(string); ok {
-				return nil, fmt.Errorf("API error: %s", message)
-			}
+	// Add a GIF, which is a link card to its Tenor URL
+	if opts.GIF != nil && opts.DryRun {
+		record["embed"] = dryRunGIFEmbed(*opts.GIF)
+	} else if opts.GIF != nil {
+		embed, err := buildGIFEmbed(token, *opts.GIF)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, string(respBody))
+		record["embed"] = embed
 	}
 
-	// Decode the response
-	var blobResp UploadBlobResponse
-	if err := json.Unmarshal(respBody, &blobResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w - body: %s", err, string(respBody))
+	// Add a video if provided
+	if opts.Video != nil && opts.DryRun {
+		embed, err := dryRunVideoEmbed(*opts.Video)
+		if err != nil {
+			return nil, err
+		}
+		record["embed"] = embed
+	} else if opts.Video != nil {
+		embed, err := buildVideoEmbed(token, *opts.Video)
+		if err != nil {
+			return nil, err
+		}
+		record["embed"] = embed
 	}
 
-	if blobResp.Blob.Ref.Link == "" {
-		return nil, fmt.Errorf("invalid response: missing blob reference link - body: %s", string(respBody))
+	// Add image attachments if provided
+	if len(opts.Images) > 0 {
+		embed, err := buildImagesEmbed(token, opts.Images, opts.DedupeImages, opts.ImageOptions, opts.DryRun)
+		if err != nil {
+			return nil, err
+		}
+		record["embed"] = embed
 	}
 
-	slog.Info("Image uploaded successfully", "blob_link", blobResp.Blob.Ref.Link, "size", len(imgData))
-	return &blobResp, nil
+	return record, nil
 }
 
-// getMimeType tries to determine the MIME type of a file based on its extension
-func getMimeType(filename string) string {
-	ext := strings.ToLower(filepath.Ext(filename))
-	switch ext {
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".png":
-		return "image/png"
-	case ".gif":
-		return "image/gif"
-	case ".webp":
-		return "image/webp"
-	default:
-		// Try to infer from file content if needed
-		return ""
+// newPostRecord builds a bare app.bsky.feed.post record stamped with the current time
+func newPostRecord(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"$type":     "app.bsky.feed.post",
+		"text":      text,
+		"createdAt": getCurrentTime(),
 	}
 }
 
-// getImageDimensions tries to determine the width and height of an image file
-func getImageDimensions(imagePath string) (int, int, error) {
-	file, err := os.Open(imagePath)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to open image for dimension detection: %w", err)
-	}
-	defer file.Close()
+// timestampFormat is RFC 3339 in UTC with exactly three fractional digits,
+// which RFC3339Nano can't give as it drops trailing zeros
+const timestampFormat = "2006-01-02T15:04:05.000Z"
 
-	img, _, err := image.DecodeConfig(file)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to decode image dimensions: %w", err)
+// getCurrentTime returns the current time in the format required by Bluesky
+func getCurrentTime() string {
+	return formatTimestamp(time.Now())
+}
+
+// ValidateCreatedAt checks that a backdated createdAt isn't in the future
+func ValidateCreatedAt(t time.Time) error {
+	if t.After(time.Now().Add(maxClockSkew)) {
+		return fmt.Errorf("createdAt %s is in the future", formatTimestamp(t))
 	}
+	return nil
+}
 
-	return img.Width, img.Height, nil
+// formatTimestamp formats a time for a record's createdAt, in UTC with
+// millisecond precision. Bluesky prefers the "Z" format over "+00:00".
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampFormat)
 }
 
 // Updated response structure
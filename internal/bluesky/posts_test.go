@@ -0,0 +1,28 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	paris := time.FixedZone("CET", 60*60)
+	tests := []struct {
+		name string
+		time time.Time
+		want string
+	}{
+		{"utc", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), "2025-01-02T03:04:05.000Z"},
+		{"milliseconds", time.Date(2025, 1, 2, 3, 4, 5, 678900000, time.UTC), "2025-01-02T03:04:05.678Z"},
+		{"other zone", time.Date(2025, 1, 2, 4, 4, 5, 0, paris), "2025-01-02T03:04:05.000Z"},
+		{"other zone across midnight", time.Date(2025, 1, 1, 0, 30, 0, 0, paris), "2024-12-31T23:30:00.000Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTimestamp(tt.time); got != tt.want {
+				t.Errorf("formatTimestamp(%v) = %q, want %q", tt.time, got, tt.want)
+			}
+		})
+	}
+}
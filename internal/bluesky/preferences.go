@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	contentLabelPrefType = "app.bsky.actor.defs#contentLabelPref"
+	adultContentPrefType = "app.bsky.actor.defs#adultContentPref"
+)
+
+// LabelVisibilities are the ways a labelled post can be shown
+var LabelVisibilities = map[string]bool{
+	"ignore": true,
+	"show":   true,
+	"warn":   true,
+	"hide":   true,
+}
+
+// DefaultLabelVisibility is how the Bluesky app treats the global labels when no preference is set
+var DefaultLabelVisibility = map[string]string{
+	"porn":          "hide",
+	"sexual":        "warn",
+	"nudity":        "ignore",
+	"graphic-media": "warn",
+}
+
+// adultLabels need adult content enabled for anything but hide to take effect
+var adultLabels = map[string]bool{
+	"porn":   true,
+	"sexual": true,
+	"nudity": true,
+}
+
+// ContentLabelPref is how posts carrying a label are shown. Without a labeler
+// DID it applies to Bluesky's own global labels.
+type ContentLabelPref struct {
+	Type       string `json:"$type"`
+	LabelerDID string `json:"labelerDid,omitempty"`
+	Label      string `json:"label"`
+	Visibility string `json:"visibility"`
+}
+
+// Preferences are an account's preference entries, kept raw so entries yabc
+// doesn't know about are written back unchanged
+type Preferences []json.RawMessage
+
+// GetPreferences fetches the account's preferences
+func GetPreferences(token *DIDResponse) (Preferences, error) {
+	var resp struct {
+		Preferences Preferences `json:"preferences"`
+	}
+	if err := xrpcGet(token, "app.bsky.actor.getPreferences", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return resp.Preferences, nil
+}
+
+// PutPreferences replaces the account's preferences
+func PutPreferences(token *DIDResponse, prefs Preferences) error {
+	if err := xrpcPost(token, "app.bsky.actor.putPreferences", map[string]interface{}{"preferences": prefs}, nil); err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
+
+// prefType reads the $type of a raw preference entry
+func prefType(pref json.RawMessage) string {
+	var typed struct {
+		Type string `json:"$type"`
+	}
+	json.Unmarshal(pref, &typed)
+	return typed.Type
+}
+
+// ContentLabels returns the content label preferences
+func (p Preferences) ContentLabels() []ContentLabelPref {
+	var labels []ContentLabelPref
+	for _, pref := range p {
+		if prefType(pref) != contentLabelPrefType {
+			continue
+		}
+		var label ContentLabelPref
+		if err := json.Unmarshal(pref, &label); err == nil {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// AdultContentEnabled reports whether the account has opted in to adult content
+func (p Preferences) AdultContentEnabled() bool {
+	for _, pref := range p {
+		if prefType(pref) != adultContentPrefType {
+			continue
+		}
+		var adult struct {
+			Enabled bool `json:"enabled"`
+		}
+		json.Unmarshal(pref, &adult)
+		return adult.Enabled
+	}
+	return false
+}
+
+// SetContentLabel sets how a global label is shown, replacing any existing
+// preference for it and leaving every other entry as it was
+func (p Preferences) SetContentLabel(label, visibility string) (Preferences, error) {
+	if !LabelVisibilities[visibility] {
+		return nil, fmt.Errorf("invalid visibility %q: expected ignore, show, warn or hide", visibility)
+	}
+
+	entry, err := json.Marshal(ContentLabelPref{Type: contentLabelPrefType, Label: label, Visibility: visibility})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preference: %w", err)
+	}
+
+	var updated Preferences
+	replaced := false
+	for _, pref := range p {
+		if prefType(pref) == contentLabelPrefType {
+			var existing ContentLabelPref
+			if json.Unmarshal(pref, &existing) == nil && existing.Label == label && existing.LabelerDID == "" {
+				if !replaced {
+					updated = append(updated, entry)
+					replaced = true
+				}
+				continue
+			}
+		}
+		updated = append(updated, pref)
+	}
+	if !replaced {
+		updated = append(updated, entry)
+	}
+	return updated, nil
+}
+
+// NeedsAdultContent reports whether showing a label has no effect until adult content is enabled
+func NeedsAdultContent(label, visibility string) bool {
+	return adultLabels[label] && visibility != "hide"
+}
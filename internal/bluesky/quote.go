@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrNotAPost is returned when quoting or reposting something other than a post
+var ErrNotAPost = errors.New("not a post")
+
+// ErrPostNotFound is returned when quoting or reposting a post that doesn't exist or was deleted
+var ErrPostNotFound = errors.New("post not found or deleted")
+
+// ResolvePostRef checks uri points at an existing post and returns a strong
+// reference to it, as quotes and reposts need
+func ResolvePostRef(uri string) (StrongRef, error) {
+	parsed, err := ParseATURI(uri)
+	if err != nil {
+		return StrongRef{}, err
+	}
+	if parsed.Collection != "app.bsky.feed.post" {
+		return StrongRef{}, fmt.Errorf("%w: %s is a %s record", ErrNotAPost, uri, parsed.Collection)
+	}
+
+	record, err := GetRecord(parsed.Repo, parsed.Collection, parsed.RKey)
+	if isNotFound(err) {
+		return StrongRef{}, fmt.Errorf("%w: %s", ErrPostNotFound, uri)
+	}
+	if err != nil {
+		return StrongRef{}, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+
+	return StrongRef{URI: record.URI, CID: record.CID}, nil
+}
+
+// ErrAlreadyReposted is returned along with the existing repost when reposting a post reposted before
+var ErrAlreadyReposted = errors.New("post already reposted")
+
+// RepostPost reposts a post as is, creating an app.bsky.feed.repost record.
+// Like LikePost, it returns the existing repost with ErrAlreadyReposted
+// rather than reposting a post twice.
+func RepostPost(token *DIDResponse, uri string) (*PostCreateResponse, error) {
+	subject, err := ResolvePostRef(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if posts, err := GetPosts(token, []string{subject.URI}); err != nil {
+		slog.Warn("Could not check whether the post is already reposted", "uri", subject.URI, "error", err)
+	} else if len(posts) == 1 && posts[0].Viewer != nil && posts[0].Viewer.Repost != "" {
+		return &PostCreateResponse{URI: posts[0].Viewer.Repost}, ErrAlreadyReposted
+	}
+
+	return CreateRecord(token, "app.bsky.feed.repost", map[string]interface{}{
+		"$type":     "app.bsky.feed.repost",
+		"subject":   subject,
+		"createdAt": getCurrentTime(),
+	})
+}
+
+// quoteEmbed builds the app.bsky.embed.record embed that makes a post a quote.
+// A quote is a regular post of our own, unlike a repost which has no text.
+func quoteEmbed(quoted StrongRef) map[string]interface{} {
+	return map[string]interface{}{
+		"$type":  "app.bsky.embed.record",
+		"record": quoted,
+	}
+}
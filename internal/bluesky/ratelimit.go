@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is how many times a rate-limited request is retried unless changed with SetMaxRetries
+	DefaultMaxRetries = 3
+	// maxRetryWait is the longest yabc waits for a rate limit to reset before giving up
+	maxRetryWait = time.Minute
+	// baseRetryWait is the first backoff delay, doubled on each retry
+	baseRetryWait = time.Second
+)
+
+// maxRetries is how many times a rate-limited request is retried
+var maxRetries = DefaultMaxRetries
+
+// SetMaxRetries changes how many times a rate-limited request is retried, 0 to never retry
+func SetMaxRetries(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid number of retries %d: must be 0 or more", n)
+	}
+	maxRetries = n
+	return nil
+}
+
+// isRateLimited reports whether err is a 429 Too Many Requests response
+func isRateLimited(err error) bool {
+	var xrpcErr *XRPCError
+	return errors.As(err, &xrpcErr) && xrpcErr.StatusCode == http.StatusTooManyRequests
+}
+
+// retryRateLimited sends a request with send, retrying it while the response
+// is 429 Too Many Requests. Each retry waits for the ratelimit-reset time, or
+// an exponential backoff when that's sooner or missing. A request whose body
+// can't be sent again, or a limit that resets too far ahead, isn't retried.
+func retryRateLimited(req *http.Request, send func(*http.Request) ([]byte, http.Header, error)) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		body, header, err := send(req)
+		if !isRateLimited(err) || attempt >= maxRetries {
+			return body, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return body, err
+		}
+
+		wait := retryDelay(header, attempt, time.Now())
+		if wait > maxRetryWait {
+			return nil, fmt.Errorf("%w, rate limit resets in %s", err, wait.Round(time.Second))
+		}
+		slog.Warn("Rate limited, retrying", "url", req.URL.Redacted(), "wait", wait, "attempt", attempt+1, "maxRetries", maxRetries)
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		// The body was consumed by the previous attempt
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+		}
+		req = retry
+	}
+}
+
+// retryDelay returns how long to wait before retry number attempt: until the
+// ratelimit-reset time (seconds since the epoch), but at least the backoff
+func retryDelay(header http.Header, attempt int, now time.Time) time.Duration {
+	wait := baseRetryWait << attempt
+	if reset, err := strconv.ParseInt(header.Get("ratelimit-reset"), 10, 64); err == nil {
+		if untilReset := time.Unix(reset, 0).Sub(now); untilReset > wait {
+			wait = untilReset
+		}
+	}
+	return wait
+}
+
+// logRateLimit logs how much of the rate limit is left after a response, when the server says
+func logRateLimit(req *http.Request, header http.Header) {
+	remaining := header.Get("ratelimit-remaining")
+	if remaining == "" {
+		return
+	}
+	slog.Debug("Rate limit", "url", req.URL.Redacted(), "remaining", remaining, "limit", header.Get("ratelimit-limit"), "reset", header.Get("ratelimit-reset"), "policy", header.Get("ratelimit-policy"))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// RecordResponse is the response from fetching a single record
+type RecordResponse struct {
+	URI   string          `json:"uri"`
+	CID   string          `json:"cid"`
+	Value json.RawMessage `json:"value"`
+}
+
+// GetRecord fetches a single record from a repo, keeping its value as raw JSON
+func GetRecord(repo, collection, rkey string) (*RecordResponse, error) {
+	query := url.Values{}
+	query.Set("repo", repo)
+	query.Set("collection", collection)
+	query.Set("rkey", rkey)
+
+	var record RecordResponse
+	if err := xrpcGet(nil, "com.atproto.repo.getRecord", query, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// CreateRecord creates a record in the authenticated user's repo
+func CreateRecord(token *DIDResponse, collection string, record map[string]interface{}) (*PostCreateResponse, error) {
+	return createRecordWithKey(token, collection, "", record)
+}
+
+// createRecordWithKey creates a record under a specific rkey, or a generated one if rkey is empty
+func createRecordWithKey(token *DIDResponse, collection, rkey string, record map[string]interface{}) (*PostCreateResponse, error) {
+	requestBody := map[string]interface{}{
+		"collection": collection,
+		"repo":       token.DID,
+		"record":     record,
+	}
+	if rkey != "" {
+		requestBody["rkey"] = rkey
+	}
+
+	var createResp PostCreateResponse
+	if err := xrpcPost(token, "com.atproto.repo.createRecord", requestBody, &createResp); err != nil {
+		return nil, err
+	}
+
+	return &createResp, nil
+}
+
+// ListRecordsResponse is a page of records from a repo collection
+type ListRecordsResponse struct {
+	Records []RecordResponse `json:"records"`
+	Cursor  string           `json:"cursor,omitempty"`
+}
+
+// ListRecords fetches a page of records from a collection in a repo
+func ListRecords(token *DIDResponse, repo, collection, cursor string, limit int) (*ListRecordsResponse, error) {
+	query := url.Values{}
+	query.Set("repo", repo)
+	query.Set("collection", collection)
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var records ListRecordsResponse
+	if err := xrpcGet(token, "com.atproto.repo.listRecords", query, &records); err != nil {
+		return nil, err
+	}
+
+	return &records, nil
+}
+
+// DeleteRecord deletes a record from the authenticated user's repo
+func DeleteRecord(token *DIDResponse, collection, rkey string) error {
+	requestBody := map[string]interface{}{
+		"collection": collection,
+		"repo":       token.DID,
+		"rkey":       rkey,
+	}
+
+	return xrpcPost(token, "com.atproto.repo.deleteRecord", requestBody, nil)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+	"github.com/alexisbcz/yabc/internal/ui"
+)
+
+// maxRemoteImageSize caps the download of an image given as a URL. It is
+// larger than the upload limit, since --compress or --max-dimension can still
+// bring the image under it, as for a file.
+const maxRemoteImageSize = 20 << 20
+
+// remoteImageExtensions name downloaded images so their type is known from the file name
+var remoteImageExtensions = map[string]string{
+	"image/jpeg":  ".jpg",
+	"image/png":   ".png",
+	"image/gif":   ".gif",
+	"image/webp":  ".webp",
+	heicMimeType:  ".heic",
+	"image/heif":  ".heic",
+	"image/pjpeg": ".jpg",
+}
+
+// IsImageURL reports whether an image is given as an http(s) URL rather than a file
+func IsImageURL(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// downloadImage saves an image from the web to a temporary file and returns
+// its path, for the caller to remove with tempfiles.Remove. The response's
+// Content-Type must be an image; servers that only say
+// application/octet-stream are trusted and the type is found from the content.
+func downloadImage(rawURL string) (string, error) {
+	req, err := http.NewRequestWithContext(requestContext, "GET", rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "yabc (+https://github.com/alexisbcz/yabc)")
+
+	// Give the download as long as an upload of the largest image allowed
+	req, cancel := withTimeout(req, uploadTimeout(maxRemoteImageSize))
+	defer cancel()
+
+	// The client follows redirects, e.g. from a short link or to a CDN
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if final := resp.Request.URL.String(); final != rawURL {
+		slog.Debug("Image URL redirected", "url", rawURL, "final", resp.Request.URL.Redacted())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: unexpected status code: %d", rawURL, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mimeType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && contentType != "" {
+		return "", fmt.Errorf("%s has an invalid Content-Type %q", rawURL, contentType)
+	}
+	ext, ok := remoteImageExtensions[mimeType]
+	if !ok && mimeType != "" && mimeType != "application/octet-stream" {
+		return "", fmt.Errorf("%s is not an image (Content-Type %s)", rawURL, mimeType)
+	}
+
+	if resp.ContentLength > maxRemoteImageSize {
+		return "", fmt.Errorf("image too large: %s is %d bytes (%d bytes maximum)", rawURL, resp.ContentLength, maxRemoteImageSize)
+	}
+
+	file, err := tempfiles.Create("yabc-image-*" + ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	// Read one byte past the limit to tell a full image from a truncated one
+	n, err := io.Copy(file, io.LimitReader(resp.Body, maxRemoteImageSize+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		tempfiles.Remove(file.Name())
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if n > maxRemoteImageSize {
+		tempfiles.Remove(file.Name())
+		return "", fmt.Errorf("image too large: %s is over %d bytes", rawURL, maxRemoteImageSize)
+	}
+
+	slog.Info("Downloaded image", "url", rawURL, "size", n, "mimeType", mimeType)
+	return file.Name(), nil
+}
+
+// prepareRemoteImage downloads an image given as a URL and inspects it like a
+// file. The temporary file is returned for the caller to remove once uploaded.
+func prepareRemoteImage(rawURL string) (*preparedImage, string, error) {
+	ui.Info("Downloading image: %s", rawURL)
+	tmp, err := downloadImage(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	img, err := prepareImage(tmp)
+	if err != nil {
+		tempfiles.Remove(tmp)
+		return nil, "", fmt.Errorf("%s: %w", rawURL, err)
+	}
+	img.path = rawURL
+	return img, tmp, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// StrongRef points at a specific version of a record
+type StrongRef struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+// ReplyRef places a post in a thread: root is the top post, parent the one being replied to
+type ReplyRef struct {
+	Root   StrongRef `json:"root"`
+	Parent StrongRef `json:"parent"`
+}
+
+// replyableCollections are the record types a thread can hang off. Threads are
+// almost always rooted at posts, but some apps root them at feeds or lists.
+var replyableCollections = map[string]bool{
+	"app.bsky.feed.post":      true,
+	"app.bsky.feed.generator": true,
+	"app.bsky.graph.list":     true,
+}
+
+// nsidPattern matches namespaced identifiers such as app.bsky.feed.post
+var nsidPattern = regexp.MustCompile(`^[a-zA-Z]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z]([a-zA-Z0-9-]*[a-zA-Z0-9])?){2,}$`)
+
+// Ref returns a strong reference to a newly created record
+func (p *PostCreateResponse) Ref() StrongRef {
+	return StrongRef{URI: p.URI, CID: p.CID}
+}
+
+// GetRecordRef fetches the current CID of the record at uri. The collection is
+// taken from the URI itself, so refs to feeds or lists work as well as posts.
+func GetRecordRef(uri string) (StrongRef, error) {
+	parsed, err := ParseATURI(uri)
+	if err != nil {
+		return StrongRef{}, err
+	}
+
+	record, err := GetRecord(parsed.Repo, parsed.Collection, parsed.RKey)
+	if err != nil {
+		return StrongRef{}, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+
+	return StrongRef{URI: record.URI, CID: record.CID}, nil
+}
+
+// NewReplyRef builds a reply ref after checking both refs point at records a thread can hang off
+func NewReplyRef(root, parent StrongRef) (*ReplyRef, error) {
+	for _, ref := range []struct {
+		name string
+		ref  StrongRef
+	}{{"root", root}, {"parent", parent}} {
+		if err := validateReplyTarget(ref.ref); err != nil {
+			return nil, fmt.Errorf("invalid reply %s: %w", ref.name, err)
+		}
+	}
+
+	return &ReplyRef{Root: root, Parent: parent}, nil
+}
+
+// validateReplyTarget checks a strong ref is complete and points at a replyable collection
+func validateReplyTarget(ref StrongRef) error {
+	uri, err := ParseATURI(ref.URI)
+	if err != nil {
+		return err
+	}
+	if ref.CID == "" {
+		return fmt.Errorf("%s has no CID", ref.URI)
+	}
+	if !nsidPattern.MatchString(uri.Collection) {
+		return fmt.Errorf("%s has an invalid collection %q", ref.URI, uri.Collection)
+	}
+	if !replyableCollections[uri.Collection] {
+		return fmt.Errorf("%s is a %s record, which can't be replied to", ref.URI, uri.Collection)
+	}
+	return nil
+}
+
+// ErrReplyParentNotFound is returned when replying to a post that doesn't exist or was deleted
+var ErrReplyParentNotFound = errors.New("parent post not found or deleted")
+
+// ErrReplyRootNotFound is returned when the thread being replied to has lost its root post
+var ErrReplyRootNotFound = errors.New("thread root post not found or deleted")
+
+// ResolveReplyRef builds the reply ref for replying to parentURI. The parent is
+// fetched for its CID and, if it is itself a reply, for the root of its thread,
+// which is then checked to still exist so we never post a dangling reply.
+func ResolveReplyRef(parentURI string) (*ReplyRef, error) {
+	uri, err := ParseATURI(parentURI)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := GetRecord(uri.Repo, uri.Collection, uri.RKey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrReplyParentNotFound, parentURI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch parent post: %w", err)
+	}
+
+	parentRef := StrongRef{URI: parent.URI, CID: parent.CID}
+
+	var parentRecord PostRecord
+	if err := json.Unmarshal(parent.Value, &parentRecord); err != nil {
+		return nil, fmt.Errorf("failed to decode parent post: %w", err)
+	}
+
+	// Replying to a top-level post: it is also the root
+	if parentRecord.Reply == nil {
+		return NewReplyRef(parentRef, parentRef)
+	}
+
+	// Replying deeper in a thread: keep the parent's root, as long as it still exists
+	root := parentRecord.Reply.Root
+	rootURI, err := ParseATURI(root.URI)
+	if err != nil {
+		return nil, fmt.Errorf("parent post has an invalid root: %w", err)
+	}
+	if _, err := GetRecord(rootURI.Repo, rootURI.Collection, rootURI.RKey); isNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrReplyRootNotFound, root.URI)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch thread root: %w", err)
+	}
+
+	return NewReplyRef(root, parentRef)
+}
+
+// CreateReply posts content as a reply to parentURI, in the same thread as the parent
+func CreateReply(token *DIDResponse, content string, parentURI string) (*PostCreateResponse, error) {
+	reply, err := ResolveReplyRef(parentURI)
+	if err != nil {
+		return nil, err
+	}
+	return CreatePost(token, content, PostOptions{Reply: reply})
+}
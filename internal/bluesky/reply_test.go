@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	testParentURI = "at://did:plc:alice/app.bsky.feed.post/parent"
+	testRootURI   = "at://did:plc:alice/app.bsky.feed.post/root"
+)
+
+// testPDS points API_URL at handler for the length of a test
+func testPDS(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	previousAPI := API_URL
+	API_URL = server.URL + "/xrpc"
+	t.Cleanup(func() { API_URL = previousAPI })
+}
+
+// notFound answers a getRecord as a PDS does for a missing record, either as
+// a bare 404 or as an XRPC RecordNotFound error
+func notFound(w http.ResponseWriter, bare bool) {
+	if bare {
+		http.NotFound(w, nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprint(w, `{"error":"RecordNotFound","message":"Could not locate record"}`)
+}
+
+// writeRecord answers a getRecord with a post, as a reply to root if it isn't empty
+func writeRecord(w http.ResponseWriter, uri, root string) {
+	value := `{"$type":"app.bsky.feed.post","text":"hello","createdAt":"2025-01-02T03:04:05.000Z"}`
+	if root != "" {
+		ref := fmt.Sprintf(`{"uri":%q,"cid":"bafyroot"}`, root)
+		value = fmt.Sprintf(`{"$type":"app.bsky.feed.post","text":"hello","createdAt":"2025-01-02T03:04:05.000Z","reply":{"root":%s,"parent":%s}}`, ref, ref)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"uri":%q,"cid":"bafyrecord","value":%s}`, uri, value)
+}
+
+func TestCreateReplyMissingPost(t *testing.T) {
+	quiet(t)
+	tests := []struct {
+		name        string
+		parentFound bool
+		bare        bool
+		want        error
+		wantMessage string
+	}{
+		{"parent 404", false, true, ErrReplyParentNotFound, "parent post not found or deleted"},
+		{"parent RecordNotFound", false, false, ErrReplyParentNotFound, "parent post not found or deleted"},
+		{"root 404", true, true, ErrReplyRootNotFound, "thread root post not found or deleted"},
+		{"root RecordNotFound", true, false, ErrReplyRootNotFound, "thread root post not found or deleted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created atomic.Int32
+			testPDS(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/com.atproto.repo.createRecord"):
+					created.Add(1)
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"uri":"at://did:plc:bob/app.bsky.feed.post/reply","cid":"bafyreply"}`)
+				case strings.HasSuffix(r.URL.Path, "/com.atproto.repo.getRecord"):
+					if r.URL.Query().Get("rkey") == "parent" && tt.parentFound {
+						writeRecord(w, testParentURI, testRootURI)
+						return
+					}
+					notFound(w, tt.bare)
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotImplemented)
+				}
+			})
+
+			token := &DIDResponse{DID: "did:plc:bob", AccessJwt: "test"}
+			_, err := CreateReply(token, "hello back", testParentURI)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("error %q doesn't say %q", err, tt.wantMessage)
+			}
+			if n := created.Load(); n != 0 {
+				t.Errorf("createRecord called %d times, want none", n)
+			}
+		})
+	}
+}
+
+func TestResolveReplyRef(t *testing.T) {
+	testPDS(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("rkey") {
+		case "parent":
+			writeRecord(w, testParentURI, testRootURI)
+		case "root":
+			writeRecord(w, testRootURI, "")
+		default:
+			notFound(w, false)
+		}
+	})
+
+	reply, err := ResolveReplyRef(testParentURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Root.URI != testRootURI || reply.Parent.URI != testParentURI {
+		t.Errorf("got root %s and parent %s, want %s and %s", reply.Root.URI, reply.Parent.URI, testRootURI, testParentURI)
+	}
+}
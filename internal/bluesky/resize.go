@@ -0,0 +1,217 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log/slog"
+
+	"golang.org/x/image/draw"
+)
+
+// DefaultImageQuality is the JPEG quality used when images are re-encoded
+const DefaultImageQuality = 85
+
+// ImageOptions controls how images are transformed before upload
+type ImageOptions struct {
+	// MaxDimension downscales images whose width or height is larger, 0 to keep them as is
+	MaxDimension int
+	// Quality is the JPEG quality (1-100) of images resized or compressed, 0 for
+	// DefaultImageQuality. Compressing goes lower if it has to.
+	Quality int
+	// Compress re-encodes images over the size limit until they fit, instead of failing
+	Compress bool
+	// StripMetadata removes EXIF, GPS and other metadata from JPEG and PNG images
+	StripMetadata bool
+}
+
+// Validate checks the options are in range, and that a quality is only given
+// when images may be re-encoded, as it would otherwise be silently ignored
+func (o ImageOptions) Validate() error {
+	if o.MaxDimension < 0 {
+		return fmt.Errorf("invalid max dimension %d: must be positive", o.MaxDimension)
+	}
+	if o.Quality != 0 && (o.Quality < 1 || o.Quality > 100) {
+		return fmt.Errorf("invalid image quality %d: must be between 1 and 100", o.Quality)
+	}
+	if o.Quality != 0 && o.MaxDimension == 0 && !o.Compress {
+		return errors.New("image quality only applies to images resized with --max-dimension or compressed with --compress")
+	}
+	return nil
+}
+
+func (o ImageOptions) quality() int {
+	if o.Quality == 0 {
+		return DefaultImageQuality
+	}
+	return o.Quality
+}
+
+// downscaleImage shrinks an image to fit within maxDimension, preserving its
+// aspect ratio, and re-encodes it as JPEG. Smaller images are left untouched.
+func downscaleImage(img *preparedImage, opts ImageOptions) error {
+	if opts.MaxDimension == 0 || (img.width <= opts.MaxDimension && img.height <= opts.MaxDimension) {
+		return nil
+	}
+	if img.mimeType == "image/gif" {
+		// Re-encoding would drop the animation
+		slog.Warn("Not resizing GIF", "path", img.path)
+		return nil
+	}
+
+	// The pixels come upright, so a rotated photo has its width and height swapped
+	src, err := img.decode()
+	if err != nil {
+		return err
+	}
+
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	width, height := fitWithin(srcWidth, srcHeight, opts.MaxDimension)
+	scaled := scaleImage(src, width, height)
+	data, err := encodeJPEG(scaled, opts.quality())
+	if err != nil {
+		return fmt.Errorf("failed to re-encode %s: %w", img.path, err)
+	}
+
+	slog.Info("Resized image", "path", img.path,
+		"from", fmt.Sprintf("%dx%d", srcWidth, srcHeight), "to", fmt.Sprintf("%dx%d", width, height),
+		"bytesBefore", img.size, "bytesAfter", len(data), "quality", opts.quality())
+	img.setData(data)
+	img.mimeType, img.width, img.height = "image/jpeg", width, height
+	// Compressing starts from the resized pixels rather than decoding the JPEG again
+	img.decoded = scaled
+	return nil
+}
+
+// fitWithin scales width and height down so neither exceeds maxDimension, preserving the aspect ratio
+func fitWithin(width, height, maxDimension int) (int, int) {
+	if width >= height {
+		return maxDimension, max(1, height*maxDimension/width)
+	}
+	return max(1, width*maxDimension/height), maxDimension
+}
+
+// scaleImage resizes an image, painting it on white since JPEG has no transparency
+func scaleImage(src image.Image, width, height int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+	return dst
+}
+
+// orientImage turns a photo upright as its EXIF orientation says it is meant
+// to be displayed. The re-encoded JPEG has no EXIF data, so the rotation
+// would otherwise be lost, leaving phone photos sideways.
+func orientImage(src image.Image, orientation uint16) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// Orientations 5 to 8 turn the photo a quarter, swapping its width and height
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if orientation >= 5 {
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+	for y := 0; y < dst.Bounds().Dy(); y++ {
+		for x := 0; x < dst.Bounds().Dx(); x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // Mirrored
+				sx, sy = w-1-x, y
+			case 3: // Upside down
+				sx, sy = w-1-x, h-1-y
+			case 4: // Mirrored upside down
+				sx, sy = x, h-1-y
+			case 5: // Mirrored, turned a quarter counterclockwise
+				sx, sy = y, x
+			case 6: // Turned a quarter counterclockwise, so displayed a quarter clockwise
+				sx, sy = y, h-1-x
+			case 7: // Mirrored, turned a quarter clockwise
+				sx, sy = w-1-y, h-1-x
+			case 8: // Turned a quarter clockwise, so displayed a quarter counterclockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, src.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// compressQualities are the JPEG qualities compressImage tries after the one
+// asked for, from best to smallest
+var compressQualities = []int{85, 75, 65, 55, 45}
+
+// minCompressDimension is the size compressImage stops shrinking images at
+const minCompressDimension = 320
+
+// compressImage re-encodes an image as JPEG at decreasing quality until it
+// fits in maxBytes, then downscales it, preserving its aspect ratio, if the
+// lowest quality isn't enough. It returns the new data and its MIME type.
+func compressImage(data []byte, maxBytes int) ([]byte, string, error) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image for compression: %w", err)
+	}
+	if format == "jpeg" {
+		src = orientImage(src, jpegOrientation(bytes.NewReader(data)))
+	}
+	compressed, _, _, err := compressDecoded(src, maxBytes, DefaultImageQuality)
+	if err != nil {
+		return nil, "", err
+	}
+	return compressed, "image/jpeg", nil
+}
+
+// compressDecoded is compressImage for an image already decoded, starting at
+// quality. It returns the JPEG and its dimensions, which shrink if downscaling
+// was needed.
+func compressDecoded(src image.Image, maxBytes, quality int) ([]byte, int, int, error) {
+	qualities := []int{quality}
+	for _, q := range compressQualities {
+		if q < quality {
+			qualities = append(qualities, q)
+		}
+	}
+
+	// Only transparent images need painting on white, which costs a full resample
+	img := src
+	if opaque, ok := src.(interface{ Opaque() bool }); !ok || !opaque.Opaque() {
+		img = scaleImage(src, src.Bounds().Dx(), src.Bounds().Dy())
+	}
+	for {
+		width, height := img.Bounds().Dx(), img.Bounds().Dy()
+		for _, quality := range qualities {
+			compressed, err := encodeJPEG(img, quality)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("failed to re-encode image: %w", err)
+			}
+			if len(compressed) <= maxBytes {
+				slog.Debug("Compressed image", "quality", quality, "size", fmt.Sprintf("%dx%d", width, height), "bytes", len(compressed))
+				return compressed, width, height, nil
+			}
+		}
+
+		// Even the lowest quality is too large, so shrink by a quarter and try again
+		longest := max(width, height)
+		if longest <= minCompressDimension {
+			return nil, 0, 0, fmt.Errorf("could not compress image under %d bytes", maxBytes)
+		}
+		width, height = fitWithin(width, height, max(minCompressDimension, longest*3/4))
+		img = scaleImage(img, width, height)
+	}
+}
+
+// encodeJPEG encodes an image as JPEG at the given quality
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MaxSearchLimit is the most posts a page of search results can hold
+const MaxSearchLimit = 100
+
+// SearchOpts narrows a post search and picks the page of results
+type SearchOpts struct {
+	// Author only keeps posts by this handle or DID
+	Author string
+	// Since and Until only keep posts created from Since, and before Until
+	Since time.Time
+	Until time.Time
+	// Limit is the number of posts in the page, 25 if zero
+	Limit int
+	// Cursor picks the page, as returned with the previous one
+	Cursor string
+}
+
+// SearchPostsResponse is a page of posts matching a search
+type SearchPostsResponse struct {
+	Posts  []PostView `json:"posts"`
+	Cursor string     `json:"cursor,omitempty"`
+	// HitsTotal is an estimate of how many posts match in all
+	HitsTotal int `json:"hitsTotal,omitempty"`
+}
+
+// SearchPosts fetches a page of the posts matching a query, which supports
+// the same syntax as search in the Bluesky app, like "from:alice.bsky.social"
+func SearchPosts(token *DIDResponse, query string, opts SearchOpts) (*SearchPostsResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if opts.Author != "" {
+		params.Set("author", opts.Author)
+	}
+	if !opts.Since.IsZero() {
+		params.Set("since", formatTimestamp(opts.Since))
+	}
+	if !opts.Until.IsZero() {
+		params.Set("until", formatTimestamp(opts.Until))
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+
+	var results SearchPostsResponse
+	if err := xrpcGet(token, "app.bsky.feed.searchPosts", params, &results); err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+	return &results, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+)
+
+// sessionCache is whether GetToken reuses the session saved on disk and saves new ones
+var sessionCache = true
+
+// DisableSessionCache makes GetToken log in afresh and leave the session file alone
+func DisableSessionCache() {
+	sessionCache = false
+}
+
+// Session is a logged-in session persisted between runs, along with the PDS it belongs to
+type Session struct {
+	DIDResponse
+	PDS string `json:"pds"`
+}
+
+// sessionPath returns where the active profile's session is stored, under $XDG_CONFIG_HOME/yabc
+func sessionPath() (string, error) {
+	return profile.Path(profile.Active(), "session.json")
+}
+
+// SaveSession stores a session on disk, readable only by the current user
+func SaveSession(token *DIDResponse) error {
+	if token.oauth != nil {
+		return token.oauth.Save()
+	}
+
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(Session{DIDResponse: *token, PDS: PDS()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	// Replace the file in one go, so an interrupted save doesn't log the profile out
+	if err := tempfiles.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
+
+// LoadSession reads the active profile's stored session, returning nil if there is none
+func LoadSession() (*Session, error) {
+	return LoadProfileSession(profile.Active())
+}
+
+// LoadProfileSession reads a profile's stored session, returning nil if there is none
+func LoadProfileSession(name string) (*Session, error) {
+	path, err := profile.Path(name, "session.json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session file %s: %w", path, err)
+	}
+	return &session, nil
+}
+
+// belongsTo reports whether the session is for the account identified by a handle, DID or email
+func (s *Session) belongsTo(identifier string) bool {
+	identifier = strings.TrimPrefix(identifier, "@")
+	return strings.EqualFold(identifier, s.Handle) || identifier == s.DID || strings.EqualFold(identifier, s.Email)
+}
+
+// cachedToken returns the saved session for identifier if it can still be used,
+// refreshing its access token when only that has expired. It returns nil when
+// a fresh login is needed.
+func cachedToken(identifier string) *DIDResponse {
+	session, err := LoadSession()
+	if err != nil {
+		slog.Warn("Ignoring unreadable session file", "error", err)
+		return nil
+	}
+	if session == nil || (identifier != "" && !session.belongsTo(identifier)) {
+		return nil
+	}
+	// A session is only valid on the PDS it was created on
+	if pdsOverridden && session.PDS != PDS() {
+		return nil
+	}
+	if session.PDS != "" {
+		if err := SetPDS(session.PDS); err != nil {
+			return nil
+		}
+	}
+
+	token := &session.DIDResponse
+	if !jwtExpired(token.AccessJwt) {
+		slog.Debug("Using cached session", "handle", token.Handle)
+		return token
+	}
+	if jwtExpired(token.RefreshJwt) {
+		return nil
+	}
+
+	refreshed, err := RefreshToken(token)
+	if err != nil {
+		slog.Debug("Failed to refresh cached session", "error", err)
+		return nil
+	}
+	if err := SaveSession(refreshed); err != nil {
+		slog.Warn("Failed to cache session", "error", err)
+	}
+	return refreshed
+}
+
+// RemoveSession deletes the stored session, if any
+func RemoveSession() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove session file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+)
+
+// exifOrientation is the EXIF tag saying how a photo must be rotated to display upright
+const exifOrientation = 0x0112
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// keptJPEGSegments are the JPEG application segments that affect how the image
+// looks: JFIF (APP0), the ICC color profile (APP2) and Adobe's color transform
+// (APP14). Others, like EXIF and XMP (APP1) or IPTC (APP13), only carry metadata.
+var keptJPEGSegments = map[byte]bool{0xE0: true, 0xE2: true, 0xEE: true}
+
+// strippedPNGChunks are the PNG chunks that only carry metadata: EXIF, text and
+// the modification time
+var strippedPNGChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+// stripMetadata removes EXIF, XMP, IPTC and text metadata, which can hold the
+// GPS coordinates of where a photo was taken, from a JPEG or PNG. The image
+// data itself is copied as is, so nothing is lost to re-encoding. A JPEG keeps
+// its EXIF orientation, so it still displays upright. Other types are
+// returned unchanged.
+func stripMetadata(data []byte, mimeType string) ([]byte, error) {
+	var stripped []byte
+	var err error
+	switch mimeType {
+	case "image/jpeg":
+		stripped, err = stripJPEGMetadata(data)
+	case "image/png":
+		stripped, err = stripPNGMetadata(data)
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Whatever was removed, the image must still be readable, down to its dimensions
+	if _, _, err := image.DecodeConfig(bytes.NewReader(stripped)); err != nil {
+		return nil, fmt.Errorf("image is unreadable without its metadata: %w", err)
+	}
+	return stripped, nil
+}
+
+// stripJPEGMetadata copies a JPEG without its metadata segments
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("not a JPEG file")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	orientationWritten := false
+	for i := 2; i < len(data); {
+		if data[i] != 0xFF || i+1 >= len(data) {
+			return nil, fmt.Errorf("invalid JPEG marker at offset %d", i)
+		}
+		marker := data[i+1]
+		if marker == 0xFF {
+			// Fill byte before a marker
+			i++
+			continue
+		}
+
+		// The image data follows the start of scan, and is copied with everything after it
+		if marker == 0xDA || marker == 0xD9 {
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write(data[i : i+2])
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return nil, errors.New("truncated JPEG file")
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) || end < i+4 {
+			return nil, errors.New("truncated JPEG file")
+		}
+		segment := data[i:end]
+		i = end
+
+		isMetadata := (marker >= 0xE0 && marker <= 0xEF && !keptJPEGSegments[marker]) || marker == 0xFE
+		if !isMetadata {
+			out.Write(segment)
+			continue
+		}
+
+		// Only the orientation survives of the EXIF data, in an EXIF segment of its own
+		if marker == 0xE1 && !orientationWritten && bytes.HasPrefix(segment[4:], []byte(exifHeader)) {
+			if orientation := exifShort(segment[4+len(exifHeader):], exifOrientation); orientation > 1 {
+				out.Write(orientationSegment(orientation))
+				orientationWritten = true
+			}
+		}
+	}
+	return nil, errors.New("JPEG file has no image data")
+}
+
+// exifShort reads a SHORT tag from the first IFD of EXIF data, or 0 if it isn't there
+func exifShort(tiff []byte, tag uint16) uint16 {
+	order := exifByteOrder(tiff)
+	if order == nil {
+		return 0
+	}
+	entry, ok := exifEntry(tiff, order, tag, 3)
+	if !ok {
+		return 0
+	}
+	return order.Uint16(tiff[entry+8:])
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG, or 0 if it has none
+func jpegOrientation(r io.Reader) uint16 {
+	var orientation uint16
+	readJPEGSegments(bufio.NewReader(r), func(marker byte, segment []byte) {
+		if marker == 0xE1 && orientation == 0 && bytes.HasPrefix(segment, []byte(exifHeader)) {
+			orientation = exifShort(segment[len(exifHeader):], exifOrientation)
+		}
+	})
+	return orientation
+}
+
+// orientationSegment builds an EXIF segment holding nothing but the orientation
+func orientationSegment(orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2A")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{exifOrientation, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	payload := append([]byte(exifHeader), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// stripPNGMetadata copies a PNG without its metadata chunks
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("not a PNG file")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+	for i := len(pngSignature); i < len(data); {
+		// Each chunk is a length, a type, the data and a CRC
+		if i+8 > len(data) {
+			return nil, errors.New("truncated PNG file")
+		}
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errors.New("truncated PNG file")
+		}
+		chunkType := string(data[i+4 : i+8])
+		if !strippedPNGChunks[chunkType] {
+			out.Write(data[i:end])
+		}
+		i = end
+
+		if chunkType == "IEND" {
+			return out.Bytes(), nil
+		}
+	}
+	return nil, errors.New("PNG file has no IEND chunk")
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxCARHeaderSize bounds the header of a CAR file, which only lists its roots
+const maxCARHeaderSize = 1 << 16
+
+// DownloadRepo streams the account's whole repo as a CAR file into w, without
+// holding it in memory, and returns the number of bytes written
+func DownloadRepo(ctx context.Context, token *DIDResponse, w io.Writer) (int64, error) {
+	// Sync methods are served by the account's own PDS rather than the entryway
+	url := fmt.Sprintf("https://%s/xrpc/com.atproto.sync.getRepo?did=%s", pdsHost(token), url.QueryEscape(token.DID))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := token.authorize(req).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		return 0, newXRPCError(resp.StatusCode, body)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to download repo: %w", err)
+	}
+	return n, nil
+}
+
+// CheckCAR checks that r starts with a CAR header: a varint length followed by
+// a CBOR map holding the version and roots
+func CheckCAR(r io.Reader) error {
+	br := bufio.NewReader(r)
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return fmt.Errorf("not a CAR file: %w", err)
+	}
+	if length == 0 || length > maxCARHeaderSize {
+		return fmt.Errorf("not a CAR file: header length %d", length)
+	}
+
+	header := make([]byte, length)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return fmt.Errorf("not a CAR file: truncated header: %w", err)
+	}
+	if !bytes.Contains(header, []byte("roots")) || !bytes.Contains(header, []byte("version")) {
+		return fmt.Errorf("not a CAR file: header has no roots or version")
+	}
+
+	// A repo always has at least its commit block after the header
+	if _, err := br.Peek(1); err != nil {
+		return fmt.Errorf("CAR file has no blocks")
+	}
+	return nil
+}
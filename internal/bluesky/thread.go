@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/richtext"
+)
+
+// CreateThread posts each text as a reply to the one before it. opts apply to
+// the first post, so a thread can itself reply to something or carry images.
+// The posts created so far are returned even when a later one fails.
+func CreateThread(token *DIDResponse, texts []richtext.Text, opts PostOptions) ([]*PostCreateResponse, error) {
+	posts, _, err := createThread(token, texts, opts)
+	return posts, err
+}
+
+// createThread is CreateThread, also returning the record of each post. With
+// opts.DryRun, nothing is posted, and the posts are only placeholders for the
+// later parts to reply to.
+func createThread(token *DIDResponse, texts []richtext.Text, opts PostOptions) ([]*PostCreateResponse, []map[string]interface{}, error) {
+	action := "post"
+	if opts.DryRun {
+		action = "build"
+	}
+
+	var posts []*PostCreateResponse
+	var records []map[string]interface{}
+	for i, text := range texts {
+		postOpts := PostOptions{Facets: LinkFacets(text.Links), MaxGraphemes: opts.MaxGraphemes, Langs: opts.Langs, Labels: opts.Labels, NoLinkify: opts.NoLinkify, CreatedAt: threadPartTime(opts.CreatedAt, i), DryRun: opts.DryRun}
+		if i == 0 {
+			postOpts = opts
+			postOpts.Facets = append(postOpts.Facets, LinkFacets(text.Links)...)
+		} else {
+			root := posts[0].Ref()
+			if opts.Reply != nil {
+				root = opts.Reply.Root
+			}
+
+			// Placeholders aren't real refs, so can't be checked
+			postOpts.Reply = &ReplyRef{Root: root, Parent: posts[i-1].Ref()}
+			if !opts.DryRun {
+				reply, err := NewReplyRef(root, posts[i-1].Ref())
+				if err != nil {
+					return posts, records, err
+				}
+				postOpts.Reply = reply
+			}
+		}
+
+		record, err := buildPostRecord(token, text.Text, postOpts)
+		post := &PostCreateResponse{URI: DryRunPlaceholder, CID: DryRunPlaceholder}
+		if err == nil && !opts.DryRun {
+			post, err = createPostRecord(token, text.Text, record)
+		}
+		if err != nil {
+			return posts, records, fmt.Errorf("failed to %s part %d of %d: %w", action, i+1, len(texts), err)
+		}
+		posts = append(posts, post)
+		records = append(records, record)
+	}
+	return posts, records, nil
+}
+
+// threadPartTime backdates a thread's later parts along with the first, a
+// millisecond apart so they still sort in order
+func threadPartTime(createdAt time.Time, i int) time.Time {
+	if createdAt.IsZero() {
+		return createdAt
+	}
+	return createdAt.Add(time.Duration(i) * time.Millisecond)
+}
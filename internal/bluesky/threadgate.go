@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrThreadgateOnReply is returned for reply restrictions on a reply, which
+// Bluesky ignores: only the post starting a thread decides who can reply
+var ErrThreadgateOnReply = errors.New("replies can only be restricted on the first post of a thread, not on a reply")
+
+// replyRules maps --reply-allow values to threadgate rule types
+var replyRules = map[string]string{
+	"mentioned": "app.bsky.feed.threadgate#mentionRule",
+	"following": "app.bsky.feed.threadgate#followingRule",
+	"followers": "app.bsky.feed.threadgate#followerRule",
+}
+
+// ParseReplyRules validates reply-allow values and builds the matching threadgate rules.
+// Rules combine as a union: anyone matching at least one rule may reply. "nobody"
+// produces an empty rule list, which disables replies entirely.
+func ParseReplyRules(values []string) ([]map[string]interface{}, error) {
+	rules := []map[string]interface{}{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+
+		if value == "nobody" {
+			continue
+		}
+
+		ruleType, ok := replyRules[value]
+		if !ok {
+			return nil, fmt.Errorf("unknown reply rule %q (expected mentioned, following, followers or nobody)", value)
+		}
+		rules = append(rules, map[string]interface{}{"$type": ruleType})
+	}
+
+	if seen["nobody"] && len(rules) > 0 {
+		return nil, fmt.Errorf("reply rule \"nobody\" cannot be combined with other rules")
+	}
+
+	return rules, nil
+}
+
+// CreateThreadgate restricts who can reply to a post. The threadgate record must
+// share the post's rkey, which is how Bluesky ties the two together.
+func CreateThreadgate(token *DIDResponse, postURI string, rules []string) error {
+	uri, err := ParseATURI(postURI)
+	if err != nil {
+		return err
+	}
+
+	allow, err := ParseReplyRules(rules)
+	if err != nil {
+		return err
+	}
+
+	record := map[string]interface{}{
+		"$type":     "app.bsky.feed.threadgate",
+		"post":      postURI,
+		"allow":     allow,
+		"createdAt": getCurrentTime(),
+	}
+
+	if _, err := createRecordWithKey(token, "app.bsky.feed.threadgate", uri.RKey, record); err != nil {
+		return fmt.Errorf("failed to create threadgate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ATURI is a parsed at:// URI pointing at a record in a repo
+type ATURI struct {
+	Repo       string
+	Collection string
+	RKey       string
+}
+
+// ParseATURI splits an at://<repo>/<collection>/<rkey> URI into its parts
+func ParseATURI(uri string) (*ATURI, error) {
+	rest, ok := strings.CutPrefix(uri, "at://")
+	if !ok {
+		return nil, fmt.Errorf("invalid AT URI %q: must start with at://", uri)
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid AT URI %q: expected at://<repo>/<collection>/<rkey>", uri)
+	}
+
+	return &ATURI{
+		Repo:       parts[0],
+		Collection: parts[1],
+		RKey:       parts[2],
+	}, nil
+}
+
+// String formats the URI back into its at:// form
+func (u *ATURI) String() string {
+	return fmt.Sprintf("at://%s/%s/%s", u.Repo, u.Collection, u.RKey)
+}
+
+// webCollections maps the path segments of bsky.app links to record collections
+var webCollections = map[string]string{
+	"post":  "app.bsky.feed.post",
+	"feed":  "app.bsky.feed.generator",
+	"lists": "app.bsky.graph.list",
+}
+
+// ResolveURI turns whatever a user pasted (an at:// URI, a bsky.app link with
+// query strings or fragments, or a go.bsky.app short link) into an at:// URI
+func ResolveURI(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "at://") {
+		uri, err := ParseATURI(raw)
+		if err != nil {
+			return "", err
+		}
+		return uri.String(), nil
+	}
+
+	link, err := url.Parse(raw)
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+		return "", fmt.Errorf("invalid URI %q: expected an at:// URI or a bsky.app link", raw)
+	}
+
+	// Short links redirect to the canonical bsky.app URL
+	if link.Host == "go.bsky.app" {
+		if link, err = followShortLink(link); err != nil {
+			return "", err
+		}
+	}
+
+	// https://bsky.app/profile/<actor>/post/<rkey>
+	parts := strings.Split(strings.Trim(link.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "profile" || webCollections[parts[2]] == "" || parts[3] == "" {
+		return "", fmt.Errorf("invalid link %q: expected https://bsky.app/profile/<handle>/post/<id>", raw)
+	}
+
+	uri := &ATURI{Repo: parts[1], Collection: webCollections[parts[2]], RKey: parts[3]}
+	return uri.String(), nil
+}
+
+// followShortLink resolves a go.bsky.app short link to the URL it redirects to,
+// trying a HEAD request first and falling back to GET for servers that only redirect GETs
+func followShortLink(link *url.URL) (*url.URL, error) {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(requestContext, method, link.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req, cancel := withTimeout(req, requestTimeout)
+		resp, err := httpClient.Do(req)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve short link: %w", err)
+		}
+		resp.Body.Close()
+
+		// The client follows redirects, so the final request holds the canonical URL
+		final := resp.Request.URL
+		if resp.StatusCode < 400 && final.Host != link.Host {
+			final.RawQuery = ""
+			final.Fragment = ""
+			return final, nil
+		}
+	}
+
+	return nil, fmt.Errorf("short link %s did not redirect to a post", link)
+}
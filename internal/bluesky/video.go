@@ -0,0 +1,258 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/state"
+	"github.com/alexisbcz/yabc/internal/ui"
+)
+
+const (
+	// videoServiceURL is the XRPC endpoint of Bluesky's video processing service
+	videoServiceURL = "https://video.bsky.app/xrpc"
+
+	// videoPollInterval is how often a processing job is checked
+	videoPollInterval = 2 * time.Second
+
+	// videoProcessingTimeout bounds how long we wait for a job to finish
+	videoProcessingTimeout = 10 * time.Minute
+)
+
+// Video job states reported by the video service
+const (
+	JobStateCompleted = "JOB_STATE_COMPLETED"
+	JobStateFailed    = "JOB_STATE_FAILED"
+)
+
+// VideoAttachment is a video file to attach to a post
+type VideoAttachment struct {
+	Path string
+	Alt  string
+}
+
+// VideoJobStatus is the processing state of an uploaded video
+type VideoJobStatus struct {
+	JobID    string         `json:"jobId"`
+	DID      string         `json:"did"`
+	State    string         `json:"state"`
+	Progress int            `json:"progress,omitempty"`
+	Blob     *BlobReference `json:"blob,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Message  string         `json:"message,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not
+func (s *VideoJobStatus) Done() bool {
+	return s.State == JobStateCompleted || s.State == JobStateFailed
+}
+
+// GetVideoJobStatus fetches the processing state of a video job
+func GetVideoJobStatus(jobID string) (*VideoJobStatus, error) {
+	url := fmt.Sprintf("%s/app.bsky.video.getJobStatus?jobId=%s", videoServiceURL, url.QueryEscape(jobID))
+	req, err := http.NewRequestWithContext(requestContext, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video job status: %w", err)
+	}
+
+	var resp struct {
+		JobStatus VideoJobStatus `json:"jobStatus"`
+	}
+	if err := decodeJSON(body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.JobStatus, nil
+}
+
+// buildVideoEmbed uploads a video, waits for it to be processed and builds the
+// app.bsky.embed.video embed
+func buildVideoEmbed(token *DIDResponse, video VideoAttachment) (map[string]interface{}, error) {
+	status, err := UploadVideo(token, video.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err = WaitForVideo(status.JobID, func(s *VideoJobStatus) {
+		ui.Progress("Processing video: %d%%", s.Progress)
+	})
+	ui.EndProgress()
+	if err != nil {
+		return nil, err
+	}
+
+	embed := map[string]interface{}{
+		"$type": "app.bsky.embed.video",
+		"video": status.Blob,
+	}
+	if video.Alt != "" {
+		embed["alt"] = video.Alt
+	}
+	return embed, nil
+}
+
+// UploadVideo sends a video to the video service and returns its processing job.
+// Uploads can't be resumed partway, so instead the job is remembered by the
+// video's content: retrying with the same file re-attaches to the existing job
+// rather than uploading again.
+func UploadVideo(token *DIDResponse, videoPath string) (*VideoJobStatus, error) {
+	data, err := os.ReadFile(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video file: %w", err)
+	}
+	if err := validateVideo(videoPath, data); err != nil {
+		return nil, err
+	}
+	hash := contentHash(data)
+
+	// Re-attach to a job started by an earlier attempt, unless it failed
+	if job, ok, err := state.VideoJobFor(token.DID, hash); err != nil {
+		slog.Warn("Could not read saved video jobs", "error", err)
+	} else if ok {
+		status, err := GetVideoJobStatus(job.ID)
+		if err == nil && status.State != JobStateFailed {
+			ui.Info("Resuming video job: %s", job.ID)
+			return status, nil
+		}
+		slog.Info("Saved video job can't be reused, uploading again", "jobId", job.ID, "error", err)
+	}
+
+	if err := checkVideoUploadLimits(token, len(data)); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Uploading video", "path", videoPath, "size", len(data))
+	status, err := uploadVideoData(token, videoPath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	job := state.VideoJob{ID: status.JobID, Path: videoPath, Started: time.Now()}
+	if err := state.SaveVideoJob(token.DID, hash, job); err != nil {
+		slog.Warn("Could not save video job", "jobId", status.JobID, "error", err)
+	}
+	return status, nil
+}
+
+// uploadVideoData posts the video bytes to the video service
+func uploadVideoData(token *DIDResponse, videoPath string, data []byte) (*VideoJobStatus, error) {
+	serviceToken, err := getServiceAuth(token, "did:web:"+pdsHost(token), "com.atproto.repo.uploadBlob")
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"did": {token.DID}, "name": {filepath.Base(videoPath)}}
+	url := fmt.Sprintf("%s/app.bsky.video.uploadVideo?%s", videoServiceURL, query.Encode())
+	req, err := http.NewRequestWithContext(requestContext, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", serviceToken))
+	req.Header.Set("Content-Type", "video/mp4")
+
+	// Videos take far longer to send than the usual request
+	req, cancel := withTimeout(req, uploadTimeout(len(data)))
+	defer cancel()
+
+	body, err := doRequest(req)
+
+	// The service recognizes videos it has already seen and answers with their job
+	var xrpcErr *XRPCError
+	if errors.As(err, &xrpcErr) && xrpcErr.StatusCode == http.StatusConflict {
+		var existing VideoJobStatus
+		if json.Unmarshal(xrpcErr.body, &existing) == nil && existing.JobID != "" {
+			slog.Info("Video was already uploaded", "jobId", existing.JobID)
+			return &existing, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload video: %w", err)
+	}
+
+	// The job status comes either bare or wrapped in "jobStatus"
+	var resp struct {
+		VideoJobStatus
+		JobStatus *VideoJobStatus `json:"jobStatus"`
+	}
+	if err := decodeJSON(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.JobStatus != nil {
+		return resp.JobStatus, nil
+	}
+	if resp.JobID == "" {
+		return nil, fmt.Errorf("invalid response: missing job ID - body: %s", string(body))
+	}
+	return &resp.VideoJobStatus, nil
+}
+
+// WaitForVideo polls a video job until it finishes, reporting progress along the way
+func WaitForVideo(jobID string, progress func(*VideoJobStatus)) (*VideoJobStatus, error) {
+	deadline := time.Now().Add(videoProcessingTimeout)
+	for {
+		status, err := GetVideoJobStatus(jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.State {
+		case JobStateCompleted:
+			if status.Blob == nil {
+				return nil, fmt.Errorf("video job %s completed without a blob", jobID)
+			}
+			return status, nil
+		case JobStateFailed:
+			return nil, fmt.Errorf("video processing failed: %s", strings.TrimSpace(status.Error+" "+status.Message))
+		}
+
+		if progress != nil {
+			progress(status)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("video job %s still processing after %s, check it later with `yabc posts video-status %s`", jobID, videoProcessingTimeout, jobID)
+		}
+		time.Sleep(videoPollInterval)
+	}
+}
+
+// getServiceAuth gets a short-lived token letting another service act on
+// behalf of the account towards aud, for a single method
+func getServiceAuth(token *DIDResponse, aud, method string) (string, error) {
+	query := url.Values{
+		"aud": {aud},
+		"lxm": {method},
+		"exp": {fmt.Sprint(time.Now().Add(30 * time.Minute).Unix())},
+	}
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := xrpcGet(token, "com.atproto.server.getServiceAuth", query, &resp); err != nil {
+		return "", fmt.Errorf("failed to get service auth token: %w", err)
+	}
+	return resp.Token, nil
+}
+
+// pdsHost returns the host of the account's actual PDS, which can differ from
+// the entryway we log in through
+func pdsHost(token *DIDResponse) string {
+	if u, err := url.Parse(token.DIDDoc.PDSEndpoint()); err == nil && u.Host != "" {
+		return u.Host
+	}
+	u, _ := url.Parse(PDS())
+	return u.Host
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// MaxVideoSize is the largest video file Bluesky accepts
+	MaxVideoSize = 100 << 20
+
+	// MaxVideoDuration is the longest video Bluesky accepts
+	MaxVideoDuration = 3 * time.Minute
+
+	// videoServiceDID is the service the video upload limits are asked of
+	videoServiceDID = "did:web:video.bsky.app"
+)
+
+// validateVideo checks a video is within Bluesky's size and length limits
+// before anything is uploaded. A length that can't be read is left to the
+// video service to judge.
+func validateVideo(videoPath string, data []byte) error {
+	if len(data) > MaxVideoSize {
+		return fmt.Errorf("video file size too large: %s is %d MB (%d MB maximum)", videoPath, len(data)>>20, MaxVideoSize>>20)
+	}
+
+	duration, err := mp4Duration(data)
+	if err != nil {
+		slog.Warn("Could not read video duration", "path", videoPath, "error", err)
+		return nil
+	}
+	if duration > MaxVideoDuration {
+		return fmt.Errorf("video too long: %s is %s (%s maximum)", videoPath, duration.Round(time.Second), MaxVideoDuration)
+	}
+	return nil
+}
+
+// mp4Duration reads a video's duration from the movie header (mvhd) of an
+// MP4 or QuickTime file, without decoding any of the video
+func mp4Duration(data []byte) (time.Duration, error) {
+	moov, ok := findBox(data, "moov")
+	if !ok {
+		return 0, errors.New("no moov box")
+	}
+	mvhd, ok := findBox(moov, "mvhd")
+	if !ok || len(mvhd) < 4 {
+		return 0, errors.New("no mvhd box")
+	}
+
+	// Version 1 headers use 64-bit times, version 0 32-bit ones
+	var timescale, duration uint64
+	switch version := mvhd[0]; {
+	case version == 1 && len(mvhd) >= 32:
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	case version == 0 && len(mvhd) >= 20:
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	default:
+		return 0, fmt.Errorf("unsupported mvhd version %d", version)
+	}
+	if timescale == 0 {
+		return 0, errors.New("mvhd has no timescale")
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// findBox returns the content of the first box of the given type among the boxes in data
+func findBox(data []byte, boxType string) ([]byte, bool) {
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[:4]))
+		header := uint64(8)
+		switch size {
+		case 0:
+			// The box runs to the end of the file
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, false
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return nil, false
+		}
+		if bytes.Equal(data[4:8], []byte(boxType)) {
+			return data[header:size], true
+		}
+		data = data[size:]
+	}
+	return nil, false
+}
+
+// VideoUploadLimits is how much more video the account may upload today
+type VideoUploadLimits struct {
+	CanUpload            bool   `json:"canUpload"`
+	RemainingDailyVideos int    `json:"remainingDailyVideos"`
+	RemainingDailyBytes  int64  `json:"remainingDailyBytes"`
+	Message              string `json:"message,omitempty"`
+	Error                string `json:"error,omitempty"`
+}
+
+// GetVideoUploadLimits asks the video service how much more video the account may upload today
+func GetVideoUploadLimits(token *DIDResponse) (*VideoUploadLimits, error) {
+	serviceToken, err := getServiceAuth(token, videoServiceDID, "app.bsky.video.getUploadLimits")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(requestContext, "GET", videoServiceURL+"/app.bsky.video.getUploadLimits", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", serviceToken))
+
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video upload limits: %w", err)
+	}
+
+	var limits VideoUploadLimits
+	if err := decodeJSON(body, &limits); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// checkVideoUploadLimits fails early when the account can't upload this video
+// today, rather than after sending it. Limits that can't be fetched are left
+// to the upload itself to enforce.
+func checkVideoUploadLimits(token *DIDResponse, size int) error {
+	limits, err := GetVideoUploadLimits(token)
+	if err != nil {
+		slog.Warn("Could not check video upload limits", "error", err)
+		return nil
+	}
+
+	if !limits.CanUpload {
+		reason := limits.Message
+		if reason == "" {
+			reason = limits.Error
+		}
+		return fmt.Errorf("video uploads not allowed for this account: %s", reason)
+	}
+	if limits.RemainingDailyVideos <= 0 {
+		return errors.New("daily video upload limit reached, try again tomorrow")
+	}
+	if int64(size) > limits.RemainingDailyBytes {
+		return fmt.Errorf("video is %d MB but only %d MB of daily uploads remain", size>>20, limits.RemainingDailyBytes>>20)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package bluesky
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// maxResponseSize caps how much of a response body is read into memory
+const maxResponseSize = 10 << 20
+
+// XRPCError is an error response from an XRPC method, e.g.
+// {"error": "InvalidToken", "message": "Token has expired"}. Requests return it
+// as is or wrapped, so callers can branch on Name with errors.As.
+type XRPCError struct {
+	StatusCode int    `json:"-"`
+	Name       string `json:"error"`
+	Message    string `json:"message"`
+
+	// body is the raw response, for methods whose errors carry extra fields
+	body []byte
+}
+
+// Error reads like "InvalidToken: Token has expired", falling back to the
+// status code when the response didn't name the error
+func (e *XRPCError) Error() string {
+	switch {
+	case e.Name == "":
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	case e.Message == "":
+		return e.Name
+	}
+	return fmt.Sprintf("%s: %s", e.Name, e.Message)
+}
+
+// isNotFound reports whether err means the requested record or repo doesn't exist
+func isNotFound(err error) bool {
+	var xrpcErr *XRPCError
+	if !errors.As(err, &xrpcErr) {
+		return false
+	}
+	switch xrpcErr.Name {
+	case "RecordNotFound", "RepoNotFound", "NotFound":
+		return true
+	}
+	return xrpcErr.StatusCode == http.StatusNotFound
+}
+
+// doRequest sends a request and reads its body, turning any non-200 response into an *XRPCError
+func doRequest(req *http.Request) ([]byte, error) {
+	return doRequestWith(httpClient, req)
+}
+
+// doAuthRequest sends a request on behalf of the logged-in account. An expired
+// access token is refreshed once and the request retried, so long-running
+// commands keep working without logging in again.
+func doAuthRequest(token *DIDResponse, req *http.Request) ([]byte, error) {
+	body, err := doRequestWith(token.authorize(req), req)
+	if !isExpiredToken(err) || token == nil || token.oauth != nil || token.RefreshJwt == "" {
+		return body, err
+	}
+	// The body was consumed by the first attempt and can only be sent again if it can be rewound
+	if req.Body != nil && req.GetBody == nil {
+		return body, err
+	}
+
+	slog.Debug("Access token expired, refreshing", "url", req.URL.Redacted())
+	refreshed, refreshErr := RefreshToken(token)
+	if refreshErr != nil {
+		slog.Warn("Failed to refresh expired token", "error", refreshErr)
+		return body, err
+	}
+	*token = *refreshed
+
+	// The old refresh token is no longer valid, so the cached session must be replaced
+	if sessionCache {
+		if err := SaveSession(token); err != nil {
+			slog.Warn("Failed to cache session", "error", err)
+		}
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+	}
+	return doRequestWith(token.authorize(retry), retry)
+}
+
+// isExpiredToken reports whether err means the access token is no longer valid
+func isExpiredToken(err error) bool {
+	var xrpcErr *XRPCError
+	if !errors.As(err, &xrpcErr) {
+		return false
+	}
+	return xrpcErr.Name == "ExpiredToken" || xrpcErr.StatusCode == http.StatusUnauthorized
+}
+
+// doRequestWith is doRequest with a specific client. A rate-limited request is
+// retried once the limit resets, see retryRateLimited.
+func doRequestWith(client *http.Client, req *http.Request) ([]byte, error) {
+	return retryRateLimited(req, func(req *http.Request) ([]byte, http.Header, error) {
+		return sendRequest(client, req)
+	})
+}
+
+// sendRequest sends a request once, within the request timeout, returning the
+// response headers along with the body
+func sendRequest(client *http.Client, req *http.Request) ([]byte, http.Header, error) {
+	req, cancel := withTimeout(req, requestTimeout)
+	defer cancel()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	logRateLimit(req, resp.Header)
+
+	// Read one byte past the limit to tell a full body from a truncated one
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, resp.Header, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(body) > maxResponseSize {
+		return nil, resp.Header, fmt.Errorf("response body too large (over %d bytes)", maxResponseSize)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		xrpcErr := newXRPCError(resp.StatusCode, body)
+		slog.Debug("API error response", "url", req.URL.Redacted(), "status", resp.StatusCode, "error", xrpcErr.Name, "message", xrpcErr.Message)
+		return nil, resp.Header, xrpcErr
+	}
+
+	return body, resp.Header, nil
+}
+
+// newXRPCError parses an error response body, keeping it whole as the message if it isn't JSON
+func newXRPCError(statusCode int, body []byte) *XRPCError {
+	xrpcErr := &XRPCError{StatusCode: statusCode, body: body}
+	if err := json.Unmarshal(body, xrpcErr); err != nil {
+		xrpcErr.Message = string(body)
+	}
+	return xrpcErr
+}
+
+// decodeJSON decodes a response body into v, keeping the body in the error for context
+func decodeJSON(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w - body: %s", err, string(body))
+	}
+	return nil
+}
+
+// xrpcGet calls an XRPC query method and decodes the JSON response into v
+func xrpcGet(token *DIDResponse, method string, query url.Values, v interface{}) error {
+	url := fmt.Sprintf("%s/%s", API_URL, method)
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(requestContext, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := doAuthRequest(token, req)
+	if err != nil {
+		return err
+	}
+
+	return decodeJSON(body, v)
+}
+
+// xrpcPost calls an XRPC procedure with a JSON body and decodes the response into v, if not nil
+func xrpcPost(token *DIDResponse, method string, input interface{}, v interface{}) error {
+	// Procedures without input, like refreshSession, take no body at all
+	var reqBody io.Reader
+	if input != nil {
+		jsonBody, err := json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	url := fmt.Sprintf("%s/%s", API_URL, method)
+	req, err := http.NewRequestWithContext(requestContext, "POST", url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if input != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	body, err := doAuthRequest(token, req)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return decodeJSON(body, v)
+}
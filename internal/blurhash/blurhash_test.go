@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testImage draws a width by height image, pixel by pixel
+func testImage(width, height int, pixel func(x, y int) color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, pixel(x, y))
+		}
+	}
+	return img
+}
+
+func solid(x, y int) color.RGBA {
+	return color.RGBA{R: 46, G: 139, B: 87, A: 255}
+}
+
+// gradient goes from black to red across and to green down, over a mid blue
+func gradient(width, height int) func(x, y int) color.RGBA {
+	return func(x, y int) color.RGBA {
+		return color.RGBA{R: uint8(x * 255 / (width - 1)), G: uint8(y * 255 / (height - 1)), B: 128, A: 255}
+	}
+}
+
+// checker alternates white and black squares of 4 pixels
+func checker(x, y int) color.RGBA {
+	if (x/4+y/4)%2 == 0 {
+		return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	return color.RGBA{A: 255}
+}
+
+func TestEncode(t *testing.T) {
+	// Expected hashes come from the reference C encoder's algorithm, run
+	// separately over the same pixels
+	tests := []struct {
+		name        string
+		xComponents int
+		yComponents int
+		img         image.Image
+		want        string
+	}{
+		{"solid", 4, 3, testImage(32, 32, solid), "L25R%^uefQueuef*fQf*fQfQfQfQ"},
+		{"solid, dc only", 1, 1, testImage(32, 32, solid), "005R%^"},
+		{"gradient", 4, 3, testImage(32, 32, gradient(32, 32)), "L$Het82swxX8l}WDjte;gJfjfQfj"},
+		{"gradient, odd size", 4, 3, testImage(37, 23, gradient(37, 23)), "L$HewF2swxX8l}WWjtf7gJfjfQfj"},
+		{"gradient, most components", 9, 9, testImage(17, 9, gradient(17, 9)), "|$Hx.p2?wxkVSMt6SMt6SMuvR-jte=a|jIa|jIa|f%fQfQfQfQfQfQfQfQxtSgjtfka|j[a|j[a|eEf7fQf7fQf7fQf7fQx@Sgjtfka|j[a|j[a|eEf7fQf7fQf7fQf7fQx@Sgjtfka|j[a|j[a|eEf7fQf7fQf7fQf7fQ"},
+		{"checker, odd size", 5, 5, testImage(29, 31, checker), "e6Lqe9_3t7_3t7~qt7%Mt7%Mt7j[t7oft7~qt7%Mt7%Mt7j[t7oft7"},
+		{"single pixel", 4, 3, testImage(1, 1, func(x, y int) color.RGBA { return color.RGBA{R: 255, A: 255} }), "L~TI:j|c|c|c|c|c|c|c|c|c|c|c"},
+		// Larger images are sampled on a 128x128 grid, which a solid color doesn't tell apart
+		{"solid, sampled", 4, 3, testImage(300, 200, solid), "L05R%^ptfQptptfjfQfjfQfQfQfQ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.xComponents, tt.yComponents, tt.img)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	img := testImage(4, 4, solid)
+	for _, components := range [][2]int{{0, 3}, {4, 0}, {10, 3}, {4, 10}} {
+		if _, err := Encode(components[0], components[1], img); err == nil {
+			t.Errorf("Encode(%d, %d) succeeded, want an error", components[0], components[1])
+		}
+	}
+	if _, err := Encode(4, 3, image.NewRGBA(image.Rect(0, 0, 0, 0))); err == nil {
+		t.Error("Encode of an empty image succeeded, want an error")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is yabc's config file, ~/.config/yabc/config.toml. Its values are
+// defaults: the matching flags and environment variables win over them.
+type Config struct {
+	// Langs are the languages of new posts when --lang isn't given, instead of $LANG
+	Langs []string `toml:"langs,omitempty"`
+	// PDS is the PDS to use when neither --pds nor YABC_PDS is set
+	PDS string `toml:"pds,omitempty"`
+	// Linkify turns bare URLs in new posts into links, true unless set
+	Linkify *bool `toml:"linkify,omitempty"`
+	// WarnMissingAlt asks before posting images without alt text, true unless set
+	WarnMissingAlt *bool `toml:"warn_missing_alt,omitempty"`
+	// StripMetadata removes EXIF and GPS data from images before upload, true unless set
+	StripMetadata *bool `toml:"strip_metadata,omitempty"`
+	// ExpandEmoji turns :shortcode: tokens in new posts into emoji, false unless set
+	ExpandEmoji *bool `toml:"expand_emoji,omitempty"`
+	// MaxRetries is how many times a rate-limited request is retried
+	MaxRetries *int `toml:"max_retries,omitempty"`
+	// Timeout bounds each request, e.g. "1m", 0 for no limit
+	Timeout *time.Duration `toml:"timeout,omitempty"`
+	// Proxy is the proxy to send requests through, instead of the one from HTTPS_PROXY
+	Proxy string `toml:"proxy,omitempty"`
+	// Templates are named post formats, rendered with --template and --var
+	Templates map[string]string `toml:"templates,omitempty"`
+}
+
+// LinkifyEnabled reports whether bare URLs should become links
+func (c *Config) LinkifyEnabled() bool {
+	return c.Linkify == nil || *c.Linkify
+}
+
+// WarnMissingAltEnabled reports whether to ask before posting images without alt text
+func (c *Config) WarnMissingAltEnabled() bool {
+	return c.WarnMissingAlt == nil || *c.WarnMissingAlt
+}
+
+// StripMetadataEnabled reports whether to remove metadata from images before upload
+func (c *Config) StripMetadataEnabled() bool {
+	return c.StripMetadata == nil || *c.StripMetadata
+}
+
+// ExpandEmojiEnabled reports whether to turn :shortcode: tokens into emoji
+func (c *Config) ExpandEmojiEnabled() bool {
+	return c.ExpandEmoji != nil && *c.ExpandEmoji
+}
+
+// current is the config loaded at startup by Init
+var current = &Config{}
+
+// Init loads the config file for the rest of the run, see Current
+func Init() error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	current = cfg
+	return nil
+}
+
+// Current returns the config loaded by Init, or an empty one before that
+func Current() *Config {
+	return current
+}
+
+// Path returns where the config file lives
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "yabc", "config.toml"), nil
+}
+
+// Load reads the config file, returning an empty config if there is none
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	meta, err := toml.DecodeFile(path, &cfg)
+	if os.IsNotExist(err) {
+		return &cfg, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	// A misspelled key would otherwise be silently ignored
+	for _, key := range meta.Undecoded() {
+		slog.Warn("Unknown key in config file", "path", path, "key", key.String())
+	}
+	return &cfg, nil
+}
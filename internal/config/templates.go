@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// RenderTemplate renders a named template with vars, which are referenced as
+// {{.name}}. Every variable the template uses must be given.
+func (c *Config) RenderTemplate(name string, vars map[string]string) (string, error) {
+	source, ok := c.Templates[name]
+	if !ok {
+		return "", fmt.Errorf("no template named %q in the [templates] section of the config file", name)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", name, err)
+	}
+
+	// Report every missing variable at once, rather than the first one the template hits
+	var missing []string
+	for _, field := range templateFields(tmpl.Tree.Root) {
+		if _, ok := vars[field]; !ok {
+			missing = append(missing, "--var "+field+"=...")
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template %q needs %s", name, strings.Join(missing, ", "))
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// templateFields lists the top-level fields, like .day, a template refers to
+func templateFields(root parse.Node) []string {
+	seen := map[string]bool{}
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n != nil {
+				for _, child := range n.Nodes {
+					walk(child)
+				}
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n != nil {
+				for _, cmd := range n.Cmds {
+					for _, arg := range cmd.Args {
+						walk(arg)
+					}
+				}
+			}
+		case *parse.FieldNode:
+			seen[n.Ident[0]] = true
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		}
+	}
+	walk(root)
+
+	var fields []string
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ParseVars turns key=value pairs into template variables
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q, expected key=value", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
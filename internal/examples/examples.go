@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package examples
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// valueAnnotation holds a flag's sample values
+	valueAnnotation = "yabc_example_value"
+	// examplesAnnotation holds a command's examples, one per line, as "args\tflag flag..."
+	examplesAnnotation = "yabc_examples"
+)
+
+// SetValue records sample values for a flag. Flags given several values are
+// repeated in examples, once per value.
+func SetValue(cmd *cobra.Command, flag string, values ...string) {
+	// Look persistent flags up too, so group-wide flags can have sample values
+	f := cmd.Flag(flag)
+	if f == nil {
+		panic(fmt.Sprintf("examples: %s: flag %q does not exist", cmd.Name(), flag))
+	}
+	if f.Annotations == nil {
+		f.Annotations = map[string][]string{}
+	}
+	f.Annotations[valueAnnotation] = values
+}
+
+// Add registers an example invocation of cmd with the given positional args
+// (may be empty) and flags, referenced by their long names
+func Add(cmd *cobra.Command, args string, flags ...string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	line := args + "\t" + strings.Join(flags, " ")
+	if existing := cmd.Annotations[examplesAnnotation]; existing != "" {
+		line = existing + "\n" + line
+	}
+	cmd.Annotations[examplesAnnotation] = line
+}
+
+// Generate fills in the Example text of every command under root from the
+// registered examples. Flag names and sample values are looked up on the
+// commands themselves, so examples can't drift from the real flags.
+func Generate(root *cobra.Command) {
+	for _, cmd := range root.Commands() {
+		Generate(cmd)
+	}
+
+	specs := root.Annotations[examplesAnnotation]
+	if specs == "" {
+		return
+	}
+
+	var lines []string
+	for _, spec := range strings.Split(specs, "\n") {
+		args, flags, _ := strings.Cut(spec, "\t")
+
+		parts := []string{root.CommandPath()}
+		if args != "" {
+			parts = append(parts, args)
+		}
+		for _, name := range strings.Fields(flags) {
+			parts = append(parts, formatFlag(root, name)...)
+		}
+		lines = append(lines, "  "+strings.Join(parts, " "))
+	}
+
+	root.Example = strings.Join(lines, "\n")
+}
+
+// formatFlag renders a flag with its sample values, panicking if the flag
+// doesn't exist since that means an example has drifted from the command
+func formatFlag(cmd *cobra.Command, name string) []string {
+	flag := cmd.Flag(name)
+	if flag == nil {
+		panic(fmt.Sprintf("examples: %s has no --%s flag", cmd.CommandPath(), name))
+	}
+
+	if flag.Value.Type() == "bool" {
+		return []string{"--" + flag.Name}
+	}
+
+	values := flag.Annotations[valueAnnotation]
+	if len(values) == 0 {
+		values = []string{placeholder(flag)}
+	}
+
+	var parts []string
+	for _, value := range values {
+		parts = append(parts, "--"+flag.Name, quote(value))
+	}
+	return parts
+}
+
+// placeholder is used for flags without a sample value
+func placeholder(flag *pflag.Flag) string {
+	return "<" + flag.Name + ">"
+}
+
+// quote wraps values containing spaces or shell metacharacters in double quotes
+func quote(value string) string {
+	if strings.ContainsAny(value, " \t'\"!$&;|<>()*?#") && !strings.HasPrefix(value, "<") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
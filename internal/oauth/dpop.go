@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package oauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Key is the P-256 key DPoP proofs are signed with. Tokens are bound to it, so
+// it is stored along with them.
+type Key struct {
+	private *ecdsa.PrivateKey
+}
+
+// NewKey generates a DPoP key
+func NewKey() (*Key, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DPoP key: %w", err)
+	}
+	return &Key{private: private}, nil
+}
+
+// MarshalJSON stores the key as base64 PKCS #8
+func (k *Key) MarshalJSON() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode DPoP key: %w", err)
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(der))
+}
+
+// UnmarshalJSON reads a key stored by MarshalJSON
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to decode DPoP key: %w", err)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return fmt.Errorf("failed to parse DPoP key: %w", err)
+	}
+	private, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok || private.Curve != elliptic.P256() {
+		return fmt.Errorf("DPoP key is not a P-256 key")
+	}
+	k.private = private
+	return nil
+}
+
+// jwk returns the public key as a JSON Web Key
+func (k *Key) jwk() (map[string]string, error) {
+	public, err := k.private.PublicKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode DPoP public key: %w", err)
+	}
+	// Uncompressed point: 0x04 || x || y
+	point := public.Bytes()
+	return map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(point[1:33]),
+		"y":   base64.RawURLEncoding.EncodeToString(point[33:]),
+	}, nil
+}
+
+// Proof signs a DPoP proof for a request. nonce is the last one the server
+// handed out, and accessToken binds the proof to a token when calling the PDS.
+func (k *Key) Proof(method, target, nonce, accessToken string) (string, error) {
+	jwk, err := k.jwk()
+	if err != nil {
+		return "", err
+	}
+
+	// The proof covers the URL without its query or fragment
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid DPoP target %q: %w", target, err)
+	}
+	u.RawQuery, u.Fragment = "", ""
+
+	header := map[string]interface{}{
+		"typ": "dpop+jwt",
+		"alg": "ES256",
+		"jwk": jwk,
+	}
+	claims := map[string]interface{}{
+		"jti": randomString(16),
+		"htm": method,
+		"htu": u.String(),
+		"iat": time.Now().Unix(),
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	return k.sign(header, claims)
+}
+
+// sign builds a compact ES256 JWT
+func (k *Key) sign(header, claims map[string]interface{}) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign DPoP proof: %w", err)
+	}
+
+	// JWS wants the raw r || s, each padded to 32 bytes
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// randomString returns n random bytes, base64url encoded
+func randomString(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
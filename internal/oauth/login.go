@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Scope is what yabc asks to be allowed to do: everything an app password can
+const Scope = "atproto transition:generic"
+
+// loginTimeout is how long to wait for the user to approve in the browser
+const loginTimeout = 5 * time.Minute
+
+// Account is the account being logged in to, already resolved from its handle
+type Account struct {
+	DID    string
+	Handle string
+	PDS    string
+}
+
+// callback is what the authorization server sends back to the redirect URI
+type callback struct {
+	code   string
+	issuer string
+	err    error
+}
+
+// Login runs the authorization code flow with PKCE and DPoP: it pushes the
+// authorization request, opens the browser for the user to approve it, waits
+// for the redirect on a local server and exchanges the code for tokens.
+// showURL is given the URL being opened, for the user to visit if the browser
+// doesn't open.
+func Login(ctx context.Context, account Account, showURL func(authURL string)) (*Session, error) {
+	metadata, err := Discover(ctx, account.PDS)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := NewKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// atproto lets native apps without a hosted client metadata document use a
+	// localhost client ID, redirecting to a loopback address
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback server: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+	clientID := "http://localhost?" + url.Values{"redirect_uri": {redirectURI}, "scope": {Scope}}.Encode()
+
+	session := &Session{
+		DID:           account.DID,
+		Handle:        account.Handle,
+		PDS:           account.PDS,
+		Issuer:        metadata.Issuer,
+		TokenEndpoint: metadata.TokenEndpoint,
+		ClientID:      clientID,
+		DPoPKey:       key,
+	}
+
+	verifier := randomString(32)
+	challenge := sha256.Sum256([]byte(verifier))
+	state := randomString(16)
+
+	// Push the request so the browser URL only carries a reference to it
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("response_type", "code")
+	form.Set("redirect_uri", redirectURI)
+	form.Set("scope", Scope)
+	form.Set("state", state)
+	form.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	form.Set("code_challenge_method", "S256")
+	form.Set("login_hint", account.Handle)
+
+	var par struct {
+		RequestURI string `json:"request_uri"`
+	}
+	if err := postForm(ctx, key, &session.authNonce, metadata.PAREndpoint, form, &par); err != nil {
+		return nil, fmt.Errorf("failed to push authorization request: %w", err)
+	}
+
+	callbacks := make(chan callback, 1)
+	server := &http.Server{Handler: callbackHandler(state, callbacks)}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := metadata.AuthorizationEndpoint + "?" + url.Values{"client_id": {clientID}, "request_uri": {par.RequestURI}}.Encode()
+	showURL(authURL)
+	if err := openBrowser(authURL); err != nil {
+		slog.Debug("Failed to open browser", "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	var result callback
+	select {
+	case result = <-callbacks:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for approval in the browser")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+	if result.issuer != metadata.Issuer {
+		return nil, fmt.Errorf("authorization came from %q, expected %q", result.issuer, metadata.Issuer)
+	}
+
+	form = url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", result.code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", clientID)
+
+	var tokens tokenResponse
+	if err := postForm(ctx, key, &session.authNonce, metadata.TokenEndpoint, form, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if err := session.apply(&tokens); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// callbackHandler receives the redirect from the authorization server
+func callbackHandler(state string, callbacks chan<- callback) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var result callback
+		switch {
+		case query.Get("state") != state:
+			// Not the request we started, possibly forged: ignore it
+			http.Error(w, "Unknown authorization request", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			result.err = errors.New(firstNonEmpty(query.Get("error_description"), query.Get("error")))
+		default:
+			result.code = query.Get("code")
+			result.issuer = query.Get("iss")
+		}
+
+		message := "yabc is now logged in. You can close this tab."
+		if result.err != nil {
+			message = "Login failed: " + result.err.Error()
+		}
+		fmt.Fprintf(w, "<!doctype html><title>yabc</title><p>%s</p>", html.EscapeString(message))
+
+		select {
+		case callbacks <- result:
+		default:
+		}
+	})
+	return mux
+}
+
+// openBrowser opens a URL in the default browser
+func openBrowser(target string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", target).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", target).Start()
+	default:
+		return exec.Command("xdg-open", target).Start()
+	}
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotSupported is returned when a PDS has no OAuth authorization server
+var ErrNotSupported = errors.New("OAuth is not supported by this PDS")
+
+// ServerMetadata describes an authorization server's endpoints
+type ServerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	PAREndpoint           string `json:"pushed_authorization_request_endpoint"`
+}
+
+// Discover finds the authorization server protecting a PDS
+func Discover(ctx context.Context, pds string) (*ServerMetadata, error) {
+	var resource struct {
+		AuthorizationServers []string `json:"authorization_servers"`
+	}
+	err := getJSON(ctx, strings.TrimSuffix(pds, "/")+"/.well-known/oauth-protected-resource", &resource)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotSupported, err)
+	}
+	if len(resource.AuthorizationServers) == 0 {
+		return nil, ErrNotSupported
+	}
+
+	issuer := strings.TrimSuffix(resource.AuthorizationServers[0], "/")
+	var metadata ServerMetadata
+	if err := getJSON(ctx, issuer+"/.well-known/oauth-authorization-server", &metadata); err != nil {
+		return nil, fmt.Errorf("failed to fetch authorization server metadata: %w", err)
+	}
+
+	// The metadata must be about the server we asked for, and support what atproto requires
+	if metadata.Issuer != issuer {
+		return nil, fmt.Errorf("authorization server metadata is for %q, expected %q", metadata.Issuer, issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.PAREndpoint == "" {
+		return nil, fmt.Errorf("authorization server %s is missing required endpoints", issuer)
+	}
+	return &metadata, nil
+}
+
+// client sends requests to PDSes and authorization servers, see SetTransport
+var client = &http.Client{}
+
+// SetTransport changes how requests are sent, e.g. to go through a proxy
+func SetTransport(transport http.RoundTripper) {
+	client = &http.Client{Transport: transport}
+}
+
+// getJSON fetches a JSON document
+func getJSON(ctx context.Context, target string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, target)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", target, err)
+	}
+	return nil
+}
+
+// Error is an error response from the authorization server
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("authorization server error %d: %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("authorization server error %d: %s (%s)", e.StatusCode, e.Code, e.Description)
+}
+
+// postForm sends a DPoP-signed form to the authorization server, retrying once
+// with the nonce the server asks for, and decodes the JSON response into v
+func postForm(ctx context.Context, key *Key, nonce *string, endpoint string, form url.Values, v interface{}) error {
+	for attempt := 0; ; attempt++ {
+		proof, err := key.Proof("POST", endpoint, *nonce, "")
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("DPoP", proof)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		if next := resp.Header.Get("DPoP-Nonce"); next != "" {
+			*nonce = next
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			if err := json.Unmarshal(body, v); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			return nil
+		}
+
+		oauthErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(body, oauthErr); err != nil {
+			oauthErr.Code = string(body)
+		}
+		if oauthErr.Code == "use_dpop_nonce" && attempt == 0 {
+			continue
+		}
+		return oauthErr
+	}
+}
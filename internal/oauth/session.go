@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/profile"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+)
+
+// Session is a logged-in OAuth session, persisted between runs
+type Session struct {
+	DID    string `json:"did"`
+	Handle string `json:"handle"`
+	// PDS is the base URL of the account's PDS, the only server the tokens are valid for
+	PDS           string    `json:"pds"`
+	Issuer        string    `json:"issuer"`
+	TokenEndpoint string    `json:"tokenEndpoint"`
+	ClientID      string    `json:"clientId"`
+	AccessToken   string    `json:"accessToken"`
+	RefreshToken  string    `json:"refreshToken"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	DPoPKey       *Key      `json:"dpopKey"`
+
+	// Nonces handed out by the authorization server and the PDS
+	authNonce     string
+	resourceNonce string
+}
+
+// tokenResponse is the token endpoint's response
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	Sub          string `json:"sub"`
+}
+
+// apply stores freshly issued tokens in the session
+func (s *Session) apply(tokens *tokenResponse) error {
+	if tokens.TokenType != "DPoP" {
+		return fmt.Errorf("unexpected token type %q, expected DPoP", tokens.TokenType)
+	}
+	if tokens.Sub != s.DID {
+		return fmt.Errorf("tokens were issued for %s, expected %s", tokens.Sub, s.DID)
+	}
+	s.AccessToken = tokens.AccessToken
+	s.RefreshToken = tokens.RefreshToken
+	s.ExpiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	return nil
+}
+
+// Expired reports whether the access token has expired or is about to
+func (s *Session) Expired() bool {
+	return time.Now().Add(time.Minute).After(s.ExpiresAt)
+}
+
+// Refresh exchanges the refresh token for new tokens
+func (s *Session) Refresh(ctx context.Context) error {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", s.RefreshToken)
+	form.Set("client_id", s.ClientID)
+
+	var tokens tokenResponse
+	if err := postForm(ctx, s.DPoPKey, &s.authNonce, s.TokenEndpoint, form, &tokens); err != nil {
+		return fmt.Errorf("failed to refresh OAuth session: %w", err)
+	}
+	return s.apply(&tokens)
+}
+
+// sessionPath returns where the active profile's OAuth session is stored, under $XDG_CONFIG_HOME/yabc
+func sessionPath() (string, error) {
+	return profile.Path(profile.Active(), "oauth.json")
+}
+
+// Save stores the session on disk, readable only by the current user
+func (s *Session) Save() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode OAuth session: %w", err)
+	}
+
+	// Replace the file in one go, so an interrupted save doesn't lose the refresh token
+	if err := tempfiles.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write OAuth session file: %w", err)
+	}
+	return nil
+}
+
+// Load reads the active profile's stored session, returning nil if there is none
+func Load() (*Session, error) {
+	return LoadProfile(profile.Active())
+}
+
+// LoadProfile reads a profile's stored session, returning nil if there is none
+func LoadProfile(name string) (*Session, error) {
+	path, err := profile.Path(name, "oauth.json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth session file: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth session file %s: %w", path, err)
+	}
+	if session.DPoPKey == nil {
+		return nil, fmt.Errorf("OAuth session file %s has no DPoP key", path)
+	}
+	return &session, nil
+}
+
+// Remove deletes the stored session, if any
+func Remove() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove OAuth session file: %w", err)
+	}
+	return nil
+}
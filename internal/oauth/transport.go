@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// transport authorizes requests to the PDS with the session's DPoP-bound token
+type transport struct {
+	session *Session
+	base    http.RoundTripper
+}
+
+// Transport wraps base so requests carry the session's access token and a
+// DPoP proof, retrying once when the PDS asks for a new nonce
+func (s *Session) Transport(base http.RoundTripper) http.RoundTripper {
+	return &transport{session: s, base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// A PDS rotates nonces and rejects stale ones with a 401
+	if resp.StatusCode != http.StatusUnauthorized || !strings.Contains(resp.Header.Get("WWW-Authenticate"), "use_dpop_nonce") {
+		return resp, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+	}
+	return t.send(retry)
+}
+
+// send signs and sends a copy of req, keeping the nonce the PDS replies with
+func (t *transport) send(req *http.Request) (*http.Response, error) {
+	proof, err := t.session.DPoPKey.Proof(req.Method, req.URL.String(), t.session.resourceNonce, t.session.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := req.Clone(req.Context())
+	signed.Header.Set("Authorization", "DPoP "+t.session.AccessToken)
+	signed.Header.Set("DPoP", proof)
+
+	resp, err := t.base.RoundTrip(signed)
+	if err != nil {
+		return nil, err
+	}
+	if nonce := resp.Header.Get("DPoP-Nonce"); nonce != "" {
+		t.session.resourceNonce = nonce
+	}
+	return resp, nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Default is the profile used unless another is picked. Its sessions stay
+// directly under ~/.config/yabc, where they were before profiles existed.
+const Default = "default"
+
+// namePattern keeps profile names usable as directory names
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// selected is the profile picked for this run with Use, if any
+var selected string
+
+// Validate checks that a profile name is usable as a directory name
+func Validate(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// Use picks the profile for the rest of the run, e.g. from --profile
+func Use(name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+	selected = name
+	return nil
+}
+
+// Active returns the profile in use: the one picked with Use, or else the
+// one chosen with "yabc auth switch", or else Default
+func Active() string {
+	if selected != "" {
+		return selected
+	}
+	if name, err := Switched(); err == nil && name != "" {
+		return name
+	}
+	return Default
+}
+
+// configDir returns yabc's config directory
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "yabc"), nil
+}
+
+// Dir returns the directory holding a profile's sessions
+func Dir(name string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	if name == Default {
+		return dir, nil
+	}
+	return filepath.Join(dir, "profiles", name), nil
+}
+
+// Path returns where a profile keeps a file, e.g. its session.json
+func Path(name, file string) (string, error) {
+	dir, err := Dir(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// switchedPath returns where the profile chosen with "yabc auth switch" is stored
+func switchedPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profile"), nil
+}
+
+// Switched returns the profile chosen with "yabc auth switch", or "" if none was
+func Switched() (string, error) {
+	path, err := switchedPath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read profile file: %w", err)
+	}
+
+	name := strings.TrimSpace(string(data))
+	if err := Validate(name); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return name, nil
+}
+
+// Switch makes a profile the one used when --profile isn't given
+func Switch(name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+
+	path, err := switchedPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write profile file: %w", err)
+	}
+	return nil
+}
+
+// List returns the default profile followed by the named ones, sorted
+func List() ([]string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != Default && Validate(entry.Name()) == nil {
+			names = append(names, entry.Name())
+		}
+	}
+
+	// A profile switched to before logging in to it has no directory yet
+	if switched, err := Switched(); err == nil && switched != "" && switched != Default && !slices.Contains(names, switched) {
+		names = append(names, switched)
+	}
+	sort.Strings(names)
+	return append([]string{Default}, names...), nil
+}
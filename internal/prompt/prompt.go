@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+	"github.com/alexisbcz/yabc/internal/tempfiles"
+	"github.com/charmbracelet/huh"
+)
+
+// ErrCancelled is a prompt the user cancelled with Esc or Ctrl-C
+var ErrCancelled = errors.New("cancelled")
+
+// Err returns ErrCancelled for a prompt the user cancelled, for commands that
+// report errors themselves rather than through Exit, and else err as is
+func Err(err error) error {
+	if errors.Is(err, huh.ErrUserAborted) {
+		return ErrCancelled
+	}
+	return err
+}
+
+// Exit ends yabc after an interactive prompt failed. Cancelling a prompt with
+// Esc or Ctrl-C is a choice rather than a failure, so it exits quietly.
+func Exit(err error) {
+	tempfiles.Cleanup()
+	if errors.Is(err, huh.ErrUserAborted) {
+		fmt.Println("Cancelled.")
+		os.Exit(0)
+	}
+	slog.Error("Failed to get user input", "error", err)
+	os.Exit(1)
+}
+
+// ConfirmLargeFetch asks before fetching every one of expected items, when
+// there are enough of them to take a while. Anything but a yes declines.
+func ConfirmLargeFetch(expected int, noun string) bool {
+	if expected <= bluesky.LargeFetch {
+		return true
+	}
+
+	description := "This may take a while."
+	if expected > bluesky.MaxFetchAll {
+		description = fmt.Sprintf("Only the first %d will be fetched.", bluesky.MaxFetchAll)
+	}
+	return Confirm(fmt.Sprintf("Fetch all %d %s?", expected, noun), description)
+}
+
+// Confirm asks a yes/no question. Anything but a yes declines.
+func Confirm(title, description string) bool {
+	confirmed := false
+	err := huh.NewConfirm().
+		Title(title).
+		Description(description).
+		Value(&confirmed).
+		Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return false
+	}
+	if err != nil {
+		slog.Error("Failed to get user input", "error", err)
+		return false
+	}
+	return confirmed
+}
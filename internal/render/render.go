@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexisbcz/yabc/internal/bluesky"
+)
+
+// Author formats an author as "Display Name (@handle)", or just "@handle"
+func Author(author bluesky.ProfileViewBasic) string {
+	if author.DisplayName == "" {
+		return "@" + author.Handle
+	}
+	return fmt.Sprintf("%s (@%s)", author.DisplayName, author.Handle)
+}
+
+// Post prints a post with its author, timestamp, text and URI
+func Post(post bluesky.PostView) {
+	indentedPost(post, "")
+}
+
+// indentedPost prints a post with every line indented, for nesting replies
+func indentedPost(post bluesky.PostView, indent string) {
+	fmt.Printf("%s%s · %s\n", indent, Author(post.Author), Time(post.Record.CreatedAt))
+	if post.Record.Text != "" {
+		fmt.Println(indent + strings.ReplaceAll(post.Record.Text, "\n", "\n"+indent))
+	}
+	if video := post.Video(); video != nil {
+		indentedVideo(*video, indent)
+	}
+	fmt.Println(indent + post.URI)
+}
+
+// Video prints a video embed with its alt text, aspect ratio and, once
+// processed, the playlist URL to open in a player
+func Video(video bluesky.VideoView) {
+	indentedVideo(video, "")
+}
+
+func indentedVideo(video bluesky.VideoView, indent string) {
+	line := indent + "▶ Video"
+	if video.AspectRatio != nil {
+		line += fmt.Sprintf(" (%dx%d)", video.AspectRatio.Width, video.AspectRatio.Height)
+	}
+	if video.Alt != "" {
+		line += ": " + video.Alt
+	}
+	fmt.Println(line)
+
+	if video.Processing() {
+		fmt.Println(indent + "  Still processing, not playable yet")
+		return
+	}
+	fmt.Println(indent+" ", video.Playlist)
+}
+
+// Thread prints a post and its replies, each level of replies indented
+// further, down to depth levels
+func Thread(node *bluesky.ThreadViewPost, depth int) {
+	thread(node, depth, "")
+}
+
+func thread(node *bluesky.ThreadViewPost, depth int, indent string) {
+	if !node.IsPost() {
+		fmt.Println(indent + "[deleted or blocked post]")
+		fmt.Println()
+		return
+	}
+	indentedPost(node.Post, indent)
+	fmt.Println()
+
+	if depth <= 0 {
+		switch len(node.Replies) {
+		case 0:
+		case 1:
+			fmt.Printf("%s  … 1 reply not shown\n\n", indent)
+		default:
+			fmt.Printf("%s  … %d replies not shown\n\n", indent, len(node.Replies))
+		}
+		return
+	}
+	for i := range node.Replies {
+		thread(&node.Replies[i], depth-1, indent+"  ")
+	}
+}
+
+// FeedItem prints a feed item, noting who reposted it if it's a repost
+func FeedItem(item bluesky.FeedViewPost) {
+	if item.Reason != nil && strings.HasSuffix(item.Reason.Type, "#reasonRepost") {
+		fmt.Printf("↻ Reposted by %s\n", Author(item.Reason.By))
+	}
+	Post(item.Post)
+}
+
+// notificationVerbs describes each notification reason
+var notificationVerbs = map[string]string{
+	"like":    "liked your post",
+	"repost":  "reposted your post",
+	"follow":  "followed you",
+	"mention": "mentioned you",
+	"reply":   "replied to you",
+	"quote":   "quoted your post",
+}
+
+// Notification prints a notification, including the text of replies, mentions and quotes
+func Notification(n bluesky.Notification) {
+	verb, ok := notificationVerbs[n.Reason]
+	if !ok {
+		verb = n.Reason
+	}
+
+	unread := ""
+	if !n.IsRead {
+		unread = "• "
+	}
+
+	fmt.Printf("%s%s %s · %s\n", unread, Author(n.Author), verb, Time(n.IndexedAt))
+	switch n.Reason {
+	case "mention", "reply", "quote":
+		fmt.Println(n.Record.Text)
+		fmt.Println(n.URI)
+	case "like", "repost":
+		fmt.Println(n.ReasonSubject)
+	}
+}
+
+// Bytes formats a size in bytes for people, e.g. "12.3 MB"
+func Bytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rivo/uniseg"
+)
+
+// Table prints rows under a header, with columns aligned
+func Table(header []string, rows [][]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// Truncate shortens text to its first line and at most n graphemes, marking any cut with "…"
+func Truncate(text string, n int) string {
+	line, _, cut := strings.Cut(text, "\n")
+	line = strings.ReplaceAll(line, "\t", " ")
+
+	var b strings.Builder
+	state := -1
+	rest := line
+	for i := 0; rest != ""; i++ {
+		if i == n-1 && uniseg.GraphemeClusterCount(rest) > 1 {
+			cut = true
+			break
+		}
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		b.WriteString(cluster)
+	}
+
+	if cut {
+		b.WriteString("…")
+	}
+	return b.String()
+}
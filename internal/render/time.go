@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package render
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexisbcz/yabc/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+// absoluteLayout is how timestamps are shown unless --time-format says otherwise
+const absoluteLayout = "2006-01-02 15:04 MST"
+
+var (
+	// location is the zone timestamps are shown in, the local one (or $TZ) by default
+	location = time.Local
+
+	// relative shows timestamps as "5m ago" rather than a date
+	relative = false
+)
+
+// timezoneValue is a --timezone flag, validated as soon as it is parsed
+type timezoneValue struct{}
+
+func (timezoneValue) String() string { return location.String() }
+func (timezoneValue) Type() string   { return "zone" }
+
+func (timezoneValue) Set(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("unknown time zone %q, expected a name like Europe/Paris or UTC", name)
+	}
+	location = loc
+	return nil
+}
+
+// timeFormatValue is a --time-format flag, either "absolute" or "relative"
+type timeFormatValue struct{}
+
+func (timeFormatValue) Type() string { return "format" }
+
+func (timeFormatValue) String() string {
+	if relative {
+		return "relative"
+	}
+	return "absolute"
+}
+
+func (timeFormatValue) Set(format string) error {
+	switch format {
+	case "absolute":
+		relative = false
+	case "relative":
+		relative = true
+	default:
+		return fmt.Errorf("expected absolute or relative, got %q", format)
+	}
+	return nil
+}
+
+// AddTimeFlags adds --timezone and --time-format to a command and its subcommands
+func AddTimeFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Var(timezoneValue{}, "timezone", "Time zone to show times in, e.g. Europe/Paris")
+	cmd.PersistentFlags().Var(timeFormatValue{}, "time-format", "Show times as absolute or relative")
+
+	examples.SetValue(cmd, "timezone", "Europe/Paris")
+	examples.SetValue(cmd, "time-format", "relative")
+}
+
+// Time formats an RFC 3339 timestamp in the chosen zone and format, leaving
+// anything unparseable as it is
+func Time(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	if relative {
+		return relativeTime(t)
+	}
+	return t.In(location).Format(absoluteLayout)
+}
+
+// relativeTime describes how long ago t was, switching to a date after a month
+func relativeTime(t time.Time) string {
+	ago := time.Since(t)
+	switch {
+	case ago < 0:
+		return "in the future"
+	case ago < time.Minute:
+		return "just now"
+	case ago < time.Hour:
+		return fmt.Sprintf("%dm ago", int(ago.Minutes()))
+	case ago < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(ago.Hours()))
+	case ago < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(ago.Hours()/24))
+	}
+	return t.In(location).Format("2006-01-02")
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package richtext
+
+import (
+	"regexp"
+	"strings"
+)
+
+// shortcodePattern matches :shortcode: tokens, as typed on Slack or GitHub
+var shortcodePattern = regexp.MustCompile(`:[a-z0-9_+-]+:`)
+
+// emojiShortcodes are the common shortcodes, named as on GitHub and Slack
+var emojiShortcodes = map[string]string{
+	"+1":                       "👍",
+	"-1":                       "👎",
+	"100":                      "💯",
+	"alarm_clock":              "⏰",
+	"angry":                    "😠",
+	"apple":                    "🍎",
+	"art":                      "🎨",
+	"balloon":                  "🎈",
+	"beers":                    "🍻",
+	"bell":                     "🔔",
+	"birthday":                 "🎂",
+	"blush":                    "😊",
+	"books":                    "📚",
+	"boom":                     "💥",
+	"brain":                    "🧠",
+	"broken_heart":             "💔",
+	"bug":                      "🐛",
+	"bulb":                     "💡",
+	"butterfly":                "🦋",
+	"cake":                     "🍰",
+	"calendar":                 "📆",
+	"camera":                   "📷",
+	"cat":                      "🐱",
+	"chart_with_upwards_trend": "📈",
+	"check":                    "✔️",
+	"clap":                     "👏",
+	"clown_face":               "🤡",
+	"coffee":                   "☕",
+	"computer":                 "💻",
+	"confetti_ball":            "🎊",
+	"confused":                 "😕",
+	"construction":             "🚧",
+	"cool":                     "🆒",
+	"crab":                     "🦀",
+	"cry":                      "😢",
+	"crossed_fingers":          "🤞",
+	"dog":                      "🐶",
+	"eyes":                     "👀",
+	"facepalm":                 "🤦",
+	"fire":                     "🔥",
+	"flushed":                  "😳",
+	"gift":                     "🎁",
+	"globe_with_meridians":     "🌐",
+	"grin":                     "😁",
+	"grinning":                 "😀",
+	"hammer":                   "🔨",
+	"handshake":                "🤝",
+	"heart":                    "❤️",
+	"heart_eyes":               "😍",
+	"hugs":                     "🤗",
+	"hourglass":                "⌛",
+	"innocent":                 "😇",
+	"joy":                      "😂",
+	"key":                      "🔑",
+	"kiss":                     "💋",
+	"laughing":                 "😆",
+	"link":                     "🔗",
+	"lock":                     "🔒",
+	"mag":                      "🔍",
+	"memo":                     "📝",
+	"moon":                     "🌙",
+	"muscle":                   "💪",
+	"musical_note":             "🎵",
+	"nerd_face":                "🤓",
+	"ok_hand":                  "👌",
+	"package":                  "📦",
+	"partying_face":            "🥳",
+	"pencil2":                  "✏️",
+	"pensive":                  "😔",
+	"pizza":                    "🍕",
+	"point_down":               "👇",
+	"point_left":               "👈",
+	"point_right":              "👉",
+	"point_up":                 "☝️",
+	"pray":                     "🙏",
+	"pushpin":                  "📌",
+	"raised_hands":             "🙌",
+	"rainbow":                  "🌈",
+	"relaxed":                  "☺️",
+	"rocket":                   "🚀",
+	"rofl":                     "🤣",
+	"rose":                     "🌹",
+	"scream":                   "😱",
+	"see_no_evil":              "🙈",
+	"shrug":                    "🤷",
+	"skull":                    "💀",
+	"sleeping":                 "😴",
+	"slightly_smiling_face":    "🙂",
+	"smile":                    "😄",
+	"smiley":                   "😃",
+	"smirk":                    "😏",
+	"snowflake":                "❄️",
+	"sob":                      "😭",
+	"sparkles":                 "✨",
+	"star":                     "⭐",
+	"star_struck":              "🤩",
+	"stuck_out_tongue":         "😛",
+	"sun":                      "☀️",
+	"sunglasses":               "😎",
+	"sweat_smile":              "😅",
+	"tada":                     "🎉",
+	"thinking":                 "🤔",
+	"thumbsdown":               "👎",
+	"thumbsup":                 "👍",
+	"trophy":                   "🏆",
+	"turtle":                   "🐢",
+	"unamused":                 "😒",
+	"upside_down_face":         "🙃",
+	"warning":                  "⚠️",
+	"wave":                     "👋",
+	"white_check_mark":         "✅",
+	"wink":                     "😉",
+	"wrench":                   "🔧",
+	"x":                        "❌",
+	"zap":                      "⚡",
+}
+
+// ExpandEmoji replaces known :shortcode: tokens, like :rocket:, with their
+// emoji. Unknown ones are left as typed, and so is anything inside a URL.
+func ExpandEmoji(text string) string {
+	links := DetectLinks(text)
+	inLink := func(start, end int) bool {
+		for _, link := range links {
+			if start < link.End && end > link.Start {
+				return true
+			}
+		}
+		return false
+	}
+
+	var b strings.Builder
+	last := 0
+	for pos := 0; pos < len(text); {
+		m := shortcodePattern.FindStringIndex(text[pos:])
+		if m == nil {
+			break
+		}
+		start, end := pos+m[0], pos+m[1]
+		emoji, ok := emojiShortcodes[text[start+1:end-1]]
+		if !ok || inLink(start, end) {
+			// The closing colon may open the next shortcode, as in "10:30:rocket:"
+			pos = end - 1
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(emoji)
+		last, pos = end, end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
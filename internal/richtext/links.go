@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package richtext
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// urlPattern matches http(s) URLs up to the next whitespace
+var urlPattern = regexp.MustCompile(`https?://[^\s<>]+`)
+
+// DetectLinks finds the bare URLs in text, leaving out trailing punctuation
+// that belongs to the sentence rather than the URL
+func DetectLinks(text string) []Link {
+	var links []Link
+	for _, m := range urlPattern.FindAllStringIndex(text, -1) {
+		uri := trimURL(text[m[0]:m[1]])
+		links = append(links, Link{Start: m[0], End: m[0] + len(uri), URI: uri})
+	}
+	return links
+}
+
+// trimURL drops trailing punctuation, keeping closing brackets that have a match in the URL
+func trimURL(uri string) string {
+	for uri != "" {
+		last := uri[len(uri)-1]
+		switch {
+		case strings.IndexByte(`.,;:!?'"`, last) >= 0:
+		case last == ')' && strings.Count(uri, "(") < strings.Count(uri, ")"):
+		case last == ']' && strings.Count(uri, "[") < strings.Count(uri, "]"):
+		default:
+			return uri
+		}
+		uri = uri[:len(uri)-1]
+	}
+	return uri
+}
+
+// WithLinks adds the bare URLs of a text to its links, skipping those already covered
+func WithLinks(t Text) Text {
+	for _, link := range DetectLinks(t.Text) {
+		overlaps := false
+		for _, existing := range t.Links {
+			if link.Start < existing.End && existing.Start < link.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			t.Links = append(t.Links, link)
+		}
+	}
+	sort.Slice(t.Links, func(i, j int) bool { return t.Links[i].Start < t.Links[j].Start })
+	return t
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package richtext
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// markdownLink matches [label](url), with an optional "title" after the url
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\((\S+?)(?:\s+"[^"]*")?\)`)
+
+	// markdownHeading matches an ATX heading, capturing its level and text
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+)
+
+// SplitOnDelimiter breaks text into segments at every line that holds only
+// the delimiter, e.g. "---", dropping segments left empty
+func SplitOnDelimiter(text, delimiter string) []string {
+	var segments []string
+	var current []string
+	flush := func() {
+		if segment := strings.TrimSpace(strings.Join(current, "\n")); segment != "" {
+			segments = append(segments, segment)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == delimiter {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return segments
+}
+
+// SplitMarkdown breaks a markdown document into sections, starting a new one
+// at every "##" heading, or at every paragraph when byParagraph is set.
+// Heading markers are dropped, keeping the heading text as the section's first line.
+func SplitMarkdown(md string, byParagraph bool) []string {
+	var sections []string
+	var current []string
+	flush := func() {
+		if section := strings.TrimSpace(strings.Join(current, "\n")); section != "" {
+			sections = append(sections, section)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			if byParagraph || len(m[1]) == 2 {
+				flush()
+			}
+			current = append(current, m[2])
+			continue
+		}
+		if byParagraph && strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	// Collapse runs of blank lines left between paragraphs
+	for i, section := range sections {
+		for strings.Contains(section, "\n\n\n") {
+			section = strings.ReplaceAll(section, "\n\n\n", "\n\n")
+		}
+		sections[i] = section
+	}
+	return sections
+}
+
+// FromMarkdown replaces [label](url) links with their label, keeping the URL as a link
+func FromMarkdown(md string) Text {
+	var t Text
+	var b strings.Builder
+	last := 0
+	for _, m := range markdownLink.FindAllStringSubmatchIndex(md, -1) {
+		b.WriteString(md[last:m[0]])
+		label, uri := md[m[2]:m[3]], md[m[4]:m[5]]
+
+		start := b.Len()
+		b.WriteString(label)
+		t.Links = append(t.Links, Link{Start: start, End: b.Len(), URI: uri})
+		last = m[1]
+	}
+	b.WriteString(md[last:])
+
+	t.Text = b.String()
+	return t
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package richtext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// MaxLength is the most graphemes Bluesky allows in a post's text
+const MaxLength = 300
+
+// Link is a span of text pointing at a URL, as byte offsets into the text
+type Link struct {
+	Start int
+	End   int
+	URI   string
+}
+
+// Text is post text along with the links it carries
+type Text struct {
+	Text  string
+	Links []Link
+}
+
+// Length counts graphemes the way Bluesky does, so an emoji made of several
+// code points counts once
+func Length(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// unit is a span of text that must not be split: a word, or a whole link
+type unit struct {
+	start, end int
+}
+
+// Split breaks text into chunks of at most max graphemes. It breaks at line
+// breaks first, then sentence ends, then between words, and never inside a
+// word, emoji or link unless a single one is longer than max.
+func Split(t Text, max int) []Text {
+	units := splitUnits(t)
+
+	var chunks []Text
+	for i := 0; i < len(units); {
+		start := units[i].start
+
+		// Find the furthest unit that still fits, remembering the best break points on the way
+		lastFit, lastSentence, lastLine := -1, -1, -1
+		for j := i; j < len(units); j++ {
+			if Length(t.Text[start:units[j].end]) > max {
+				break
+			}
+			lastFit = j
+			if j+1 == len(units) {
+				break
+			}
+			gap := t.Text[units[j].end:units[j+1].start]
+			if strings.Contains(gap, "\n") {
+				lastLine = j
+			}
+			if endsSentence(t.Text[units[j].start:units[j].end]) {
+				lastSentence = j
+			}
+		}
+
+		if lastFit == -1 {
+			// A single word or link longer than max can only be cut by graphemes
+			head, rest := cutGraphemes(t.Text[start:units[i].end], max)
+			chunks = append(chunks, slice(t, start, start+len(head)))
+			units[i].start = start + len(head)
+			if rest == "" {
+				i++
+			}
+			continue
+		}
+
+		end := lastFit
+		if lastFit+1 < len(units) {
+			// Prefer a natural break unless it would leave a very short chunk
+			minEnd := units[i].start + len(t.Text[start:units[lastFit].end])/2
+			switch {
+			case lastLine >= 0 && units[lastLine].end >= minEnd:
+				end = lastLine
+			case lastSentence >= 0 && units[lastSentence].end >= minEnd:
+				end = lastSentence
+			}
+		}
+
+		chunks = append(chunks, slice(t, start, units[end].end))
+		i = end + 1
+	}
+	return chunks
+}
+
+// splitUnits finds the words of the text, merging the words of each link into one unit
+func splitUnits(t Text) []unit {
+	var units []unit
+	start := -1
+	for i, r := range t.Text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				units = append(units, unit{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		units = append(units, unit{start, len(t.Text)})
+	}
+
+	var merged []unit
+	for _, u := range units {
+		if n := len(merged); n > 0 && insideSameLink(t.Links, merged[n-1], u) {
+			merged[n-1].end = u.end
+			continue
+		}
+		merged = append(merged, u)
+	}
+	return merged
+}
+
+// insideSameLink reports whether a link spans the gap between two units
+func insideSameLink(links []Link, a, b unit) bool {
+	for _, link := range links {
+		if link.Start < b.start && link.End > a.end {
+			return true
+		}
+	}
+	return false
+}
+
+// endsSentence reports whether a word ends a sentence, allowing closing quotes and brackets
+func endsSentence(word string) bool {
+	word = strings.TrimRight(word, `"')]”’»`)
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+// cutGraphemes splits s after at most n graphemes
+func cutGraphemes(s string, n int) (string, string) {
+	rest := s
+	state := -1
+	for i := 0; i < n && rest != ""; i++ {
+		_, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+	}
+	return s[:len(s)-len(rest)], rest
+}
+
+// slice returns the part of t between two byte offsets, with its links moved along
+func slice(t Text, start, end int) Text {
+	chunk := Text{Text: t.Text[start:end]}
+	for _, link := range t.Links {
+		if link.Start >= start && link.End <= end {
+			chunk.Links = append(chunk.Links, Link{Start: link.Start - start, End: link.End - start, URI: link.URI})
+		}
+	}
+	return chunk
+}
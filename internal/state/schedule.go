@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduledImage is an image of a scheduled post, kept as a path until it is posted
+type ScheduledImage struct {
+	Path string `json:"path"`
+	Alt  string `json:"alt,omitempty"`
+}
+
+// ScheduledPost is a post waiting in the local queue until its time comes.
+// Media stay files on disk and the post being replied to is only looked up
+// when it is posted, so everything is checked again then.
+type ScheduledPost struct {
+	ID string    `json:"id"`
+	At time.Time `json:"at"`
+	// Profile is the account profile to post with, the default one if empty
+	Profile  string           `json:"profile,omitempty"`
+	Text     string           `json:"text"`
+	Images   []ScheduledImage `json:"images,omitempty"`
+	Video    string           `json:"video,omitempty"`
+	VideoAlt string           `json:"videoAlt,omitempty"`
+	GIF      string           `json:"gif,omitempty"`
+	GIFAlt   string           `json:"gifAlt,omitempty"`
+	Card     string           `json:"card,omitempty"`
+	NoThumb  bool             `json:"noThumb,omitempty"`
+	ReplyTo  string           `json:"replyTo,omitempty"`
+	// ReplyAllow are the --reply-allow rules of the threadgate to create
+	ReplyAllow   []string `json:"replyAllow,omitempty"`
+	Langs        []string `json:"langs,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	NoLinkify    bool     `json:"noLinkify,omitempty"`
+	Place        string   `json:"place,omitempty"`
+	Coords       string   `json:"coords,omitempty"`
+	AutoThread   bool     `json:"autoThread,omitempty"`
+	DedupeImages bool     `json:"dedupeImages,omitempty"`
+	MaxDimension int      `json:"maxDimension,omitempty"`
+	Quality      int      `json:"quality,omitempty"`
+	Compress     bool     `json:"compress,omitempty"`
+	// KeepMetadata uploads images with their EXIF and GPS data, which is stripped by default
+	KeepMetadata bool `json:"keepMetadata,omitempty"`
+	// MaxLength is the --max-length the post was checked and split against, Bluesky's limit if 0
+	MaxLength int `json:"maxLength,omitempty"`
+}
+
+// Files returns the media files the post needs, which must still exist when
+// it is posted. Images given as URLs are only downloaded then, so aren't files.
+func (p ScheduledPost) Files() []string {
+	var files []string
+	for _, image := range p.Images {
+		if lower := strings.ToLower(image.Path); strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+			continue
+		}
+		files = append(files, image.Path)
+	}
+	if p.Video != "" {
+		files = append(files, p.Video)
+	}
+	return files
+}
+
+func schedulePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "yabc", "scheduled.json"), nil
+}
+
+// ScheduledPosts returns the queued posts, soonest first
+func ScheduledPosts() ([]ScheduledPost, error) {
+	file, err := schedulePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule file: %w", err)
+	}
+
+	var posts []ScheduledPost
+	if err := json.Unmarshal(data, &posts); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule file %s: %w", file, err)
+	}
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].At.Before(posts[j].At) })
+	return posts, nil
+}
+
+func saveScheduledPosts(posts []ScheduledPost) error {
+	file, err := schedulePath()
+	if err != nil {
+		return err
+	}
+	if posts == nil {
+		posts = []ScheduledPost{}
+	}
+	return writeJSON(file, posts)
+}
+
+// lockSchedule locks the queue for a read-modify-write, so two yabc running
+// at once, like a flush from cron and a post being scheduled, don't lose or
+// repeat each other's changes
+func lockSchedule() (func(), error) {
+	file, err := schedulePath()
+	if err != nil {
+		return nil, err
+	}
+	return lock(file)
+}
+
+// SchedulePost adds a post to the queue, giving it an ID unless it is put
+// back after a failed attempt, and returns it
+func SchedulePost(post ScheduledPost) (ScheduledPost, error) {
+	unlock, err := lockSchedule()
+	if err != nil {
+		return post, err
+	}
+	defer unlock()
+
+	posts, err := ScheduledPosts()
+	if err != nil {
+		return post, err
+	}
+
+	if post.ID == "" {
+		post.ID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	posts = append(posts, post)
+	return post, saveScheduledPosts(posts)
+}
+
+// RemoveScheduledPost takes a post off the queue, reporting whether it was
+// there. Only one of several callers removing the same post at once is told
+// it was.
+func RemoveScheduledPost(id string) (bool, error) {
+	unlock, err := lockSchedule()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	posts, err := ScheduledPosts()
+	if err != nil {
+		return false, err
+	}
+
+	for i, post := range posts {
+		if post.ID == id {
+			return true, saveScheduledPosts(append(posts[:i], posts[i+1:]...))
+		}
+	}
+	return false, nil
+}
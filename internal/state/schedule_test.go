@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package state
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoveScheduledPostConcurrently(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		post, err := SchedulePost(ScheduledPost{At: time.Now(), Text: "hello"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, post.ID)
+	}
+
+	// Several flushes racing for the same posts must each post a given one at most once
+	var removed atomic.Int32
+	var wg sync.WaitGroup
+	for flush := 0; flush < 4; flush++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, id := range ids {
+				ok, err := RemoveScheduledPost(id)
+				if err != nil {
+					t.Error(err)
+				}
+				if ok {
+					removed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := removed.Load(); n != int32(len(ids)) {
+		t.Errorf("posts removed %d times, want %d", n, len(ids))
+	}
+	posts, err := ScheduledPosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("%d posts left in the queue, want none", len(posts))
+	}
+}
+
+func TestSchedulePostConcurrently(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// Posts scheduled while another yabc writes the queue must not be lost
+	const writers, perWriter = 8, 10
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				if _, err := SchedulePost(ScheduledPost{At: time.Now(), Text: "hello"}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	posts, err := ScheduledPosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != writers*perWriter {
+		t.Errorf("%d posts in the queue, want %d", len(posts), writers*perWriter)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// markers maps account DID to command name to the newest item seen by that command
+type markers map[string]map[string]time.Time
+
+// path returns where run markers are stored
+func path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "yabc", "state.json"), nil
+}
+
+func load() (markers, error) {
+	file, err := path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return markers{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	m := markers{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", file, err)
+	}
+	return m, nil
+}
+
+// LastRun returns the time of the newest item a command showed for an account,
+// and false if the command has never run for it
+func LastRun(account, command string) (time.Time, bool, error) {
+	m, err := load()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	last, ok := m[account][command]
+	return last, ok, nil
+}
+
+// SetLastRun records the time of the newest item a command showed for an account
+func SetLastRun(account, command string, last time.Time) error {
+	m, err := load()
+	if err != nil {
+		return err
+	}
+
+	if m[account] == nil {
+		m[account] = map[string]time.Time{}
+	}
+	m[account][command] = last
+
+	file, err := path()
+	if err != nil {
+		return err
+	}
+	return writeJSON(file, m)
+}
+
+// writeJSON stores v in a file readable only by the current user
+func writeJSON(file string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	// Write beside the file and rename it into place, so a reader never sees it half written
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// lockTimeout is how long to wait for another yabc to release a lock
+const lockTimeout = 10 * time.Second
+
+// staleLock is how old a lock file must be to be taken as left behind by a
+// yabc that crashed, as nothing holds one for more than a read and a write
+const staleLock = time.Minute
+
+// lock takes an exclusive lock on file for a read-modify-write, by creating
+// file.lock, which only one process can do, and returns the function that
+// releases it
+func lock(file string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	lockFile := file + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockFile) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", file, err)
+		}
+
+		if info, err := os.Stat(lockFile); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lockFile)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock %s: another yabc is using it, or remove %s if none is running", file, lockFile)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Since returns when a command last showed something for an account, or
+// fallback before now if it never ran, so first runs show a sensible window
+func Since(account, command string, fallback time.Duration) (time.Time, error) {
+	last, ok, err := LastRun(account, command)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		return time.Now().Add(-fallback), nil
+	}
+	return last, nil
+}
+
+// Newer reports whether an RFC 3339 timestamp is after since. Unparseable
+// timestamps count as newer so nothing is silently hidden.
+func Newer(timestamp string, since time.Time) bool {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	return err != nil || t.After(since)
+}
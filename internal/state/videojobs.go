@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// videoJobTTL is how long a video job is remembered, after which its
+// unreferenced blob has likely been discarded anyway
+const videoJobTTL = 24 * time.Hour
+
+// VideoJob is a video processing job started for a file
+type VideoJob struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Started time.Time `json:"started"`
+}
+
+// videoJobs maps account DID to video content hash to the job processing it
+type videoJobs map[string]map[string]VideoJob
+
+func videoJobsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "yabc", "video-jobs.json"), nil
+}
+
+func loadVideoJobs() (videoJobs, error) {
+	file, err := videoJobsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return videoJobs{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video jobs file: %w", err)
+	}
+
+	jobs := videoJobs{}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse video jobs file %s: %w", file, err)
+	}
+	return jobs, nil
+}
+
+// VideoJobFor returns the job started for a video's content, and false if there
+// is none or it is too old to reuse
+func VideoJobFor(account, hash string) (VideoJob, bool, error) {
+	jobs, err := loadVideoJobs()
+	if err != nil {
+		return VideoJob{}, false, err
+	}
+
+	job, ok := jobs[account][hash]
+	if !ok || time.Since(job.Started) > videoJobTTL {
+		return VideoJob{}, false, nil
+	}
+	return job, true, nil
+}
+
+// VideoJobsFor returns the jobs remembered for an account, keyed by content hash
+func VideoJobsFor(account string) (map[string]VideoJob, error) {
+	jobs, err := loadVideoJobs()
+	if err != nil {
+		return nil, err
+	}
+	return jobs[account], nil
+}
+
+// SaveVideoJob remembers the job started for a video's content, forgetting expired ones
+func SaveVideoJob(account, hash string, job VideoJob) error {
+	jobs, err := loadVideoJobs()
+	if err != nil {
+		return err
+	}
+
+	if jobs[account] == nil {
+		jobs[account] = map[string]VideoJob{}
+	}
+	jobs[account][hash] = job
+
+	for _, accountJobs := range jobs {
+		for key, old := range accountJobs {
+			if time.Since(old.Started) > videoJobTTL {
+				delete(accountJobs, key)
+			}
+		}
+	}
+
+	file, err := videoJobsPath()
+	if err != nil {
+		return err
+	}
+	return writeJSON(file, jobs)
+}
+
+// RemoveVideoJob forgets the job started for a video's content
+func RemoveVideoJob(account, hash string) error {
+	jobs, err := loadVideoJobs()
+	if err != nil {
+		return err
+	}
+	if _, ok := jobs[account][hash]; !ok {
+		return nil
+	}
+	delete(jobs[account], hash)
+
+	file, err := videoJobsPath()
+	if err != nil {
+		return err
+	}
+	return writeJSON(file, jobs)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package tempfiles
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+var (
+	mu    sync.Mutex
+	paths []string
+)
+
+// Create creates a temporary file and registers it for cleanup when yabc exits
+func Create(pattern string) (*os.File, error) {
+	return CreateIn("", pattern)
+}
+
+// CreateIn is like Create but puts the file in dir, e.g. next to the file it
+// will be renamed to once complete
+func CreateIn(dir, pattern string) (*os.File, error) {
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	paths = append(paths, file.Name())
+	mu.Unlock()
+
+	return file, nil
+}
+
+// WriteFile writes data to path the way os.WriteFile does, but through a
+// temporary file beside it that is renamed into place, so the file is never
+// left half written, even if yabc is interrupted
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := CreateIn(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Remove deletes a temporary file created with Create and stops tracking it
+func Remove(path string) {
+	mu.Lock()
+	paths = slices.DeleteFunc(paths, func(p string) bool { return p == path })
+	mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("Failed to remove temporary file", "path", path, "error", err)
+	}
+}
+
+// Cleanup removes every temporary file that is still registered
+func Cleanup() {
+	mu.Lock()
+	pending := paths
+	paths = nil
+	mu.Unlock()
+
+	for _, path := range pending {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			slog.Warn("Failed to remove temporary file", "path", path, "error", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Alexis Bouchez <alexbcz@proton.me> (https://alexisbouchez.com), MIT License
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	// quiet hides everything printed here, as with --quiet
+	quiet bool
+	// toStderr moves everything printed here to stderr, as with --json, so
+	// stdout only holds what scripts read
+	toStderr bool
+)
+
+// SetQuiet hides progress, success and warning messages
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// SetJSON sends progress, success and warning messages to stderr, keeping
+// stdout for JSON output
+func SetJSON(json bool) {
+	toStderr = json
+}
+
+// output returns where messages go, or nil when they are hidden
+func output() io.Writer {
+	switch {
+	case quiet:
+		return nil
+	case toStderr:
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func printf(prefix, format string, args ...interface{}) {
+	w := output()
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, prefix+format+"\n", args...)
+}
+
+// Info prints a progress message, like "Compressed photo.jpg"
+func Info(format string, args ...interface{}) {
+	printf("", format, args...)
+}
+
+// Success prints that a command did what it was asked, like "Post created successfully!"
+func Success(format string, args ...interface{}) {
+	printf("", format, args...)
+}
+
+// Progress overwrites the current line with a progress message, like
+// "Processing video: 42%", until EndProgress moves past it
+func Progress(format string, args ...interface{}) {
+	w := output()
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "\r"+format, args...)
+}
+
+// EndProgress ends the line Progress was writing to
+func EndProgress() {
+	if w := output(); w != nil {
+		fmt.Fprintln(w)
+	}
+}
+
+// Warn prints something the user should know about that didn't stop the command
+func Warn(format string, args ...interface{}) {
+	printf("Warning: ", format, args...)
+}